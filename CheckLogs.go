@@ -5,17 +5,42 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/fnv"
 	"io"
+	"math"
+	"net"
 	"net/http"
 	"os"
+	"reflect"
+	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 )
 
 const (
 	Version    = "1.1.0"
 	DefaultURL = "https://checklogs.dev"
+
+	// DefaultMaxMessageLength is the default size limit of LogData.Message
+	DefaultMaxMessageLength = 1024
+
+	// DefaultMaxContextBytes is the default size limit of the serialized
+	// LogData.Context
+	DefaultMaxContextBytes = 5000
+
+	// DefaultMaxDetailBytes is the default size limit of LogData.Detail
+	DefaultMaxDetailBytes = 16 * 1024
+
+	// DefaultMaxResponseBytes is the default size limit of API responses
+	DefaultMaxResponseBytes = 10 * 1024 * 1024
+
+	// InstanceIDEnv is the environment variable read when
+	// Options.InstanceID is not set
+	InstanceIDEnv = "CHECKLOGS_INSTANCE_ID"
 )
 
 // LogLevel represents the severity level of a log entry
@@ -31,41 +56,177 @@ const (
 
 // LogData represents a log entry
 type LogData struct {
-	Message   string                 `json:"message"`
-	Level     LogLevel               `json:"level"`
-	Source    string                 `json:"source,omitempty"`
-	UserID    *int64                 `json:"user_id,omitempty"`
-	Context   map[string]interface{} `json:"context,omitempty"`
-	Timestamp time.Time              `json:"timestamp"`
-	Hostname  string                 `json:"hostname,omitempty"`
+	Message    string                 `json:"message"`
+	Level      LogLevel               `json:"level"`
+	Source     string                 `json:"source,omitempty"`
+	UserID     *int64                 `json:"user_id,omitempty"`
+	Context    map[string]interface{} `json:"context,omitempty"`
+	Timestamp  time.Time              `json:"timestamp"`
+	Hostname   string                 `json:"hostname,omitempty"`
+	InstanceID string                 `json:"instance_id,omitempty"`
+	TraceID    string                 `json:"trace_id,omitempty"`
+	SpanID     string                 `json:"span_id,omitempty"`
+	Detail     string                 `json:"detail,omitempty"`
+	Seq        uint64                 `json:"seq,omitempty"`
+
+	// Attachments are sent as a multipart/form-data request
+	Attachments []Attachment `json:"attachments,omitempty"`
+
+	// Retry state of the AutoRetry worker, kept by in-memory queues only
+	attempts  int
+	nextRetry time.Time
+
+	// Set by the server on logs retrieved from the API
+	ID         string     `json:"id,omitempty"`
+	ReceivedAt *time.Time `json:"received_at,omitempty"`
 }
 
 // Options represents configuration for the logger
 type Options struct {
-	Source        string                 `json:"source"`
-	UserID        *int64                 `json:"user_id"`
-	Context       map[string]interface{} `json:"default_context"`
-	Silent        bool                   `json:"silent"`
-	ConsoleOutput bool                   `json:"console_output"`
-	BaseURL       string                 `json:"base_url"`
-	Timeout       time.Duration          `json:"timeout"`
+	Source                    string                                                   `json:"source"`
+	UserID                    *int64                                                   `json:"user_id"`
+	Context                   map[string]interface{}                                   `json:"default_context"`
+	Silent                    bool                                                     `json:"silent"`
+	ConsoleOutput             bool                                                     `json:"console_output"`
+	BaseURL                   string                                                   `json:"base_url"`
+	Timeout                   time.Duration                                            `json:"timeout"`
+	ConsoleBuffered           bool                                                     `json:"console_buffered"`
+	BaseURLResolver           func(ctx context.Context) (string, error)                `json:"-"`
+	BaseURLCacheTTL           time.Duration                                            `json:"base_url_cache_ttl"`
+	ContextSchema             ContextSchema                                            `json:"-"`
+	HeartbeatMessage          string                                                   `json:"heartbeat_message"`
+	HeartbeatLevel            LogLevel                                                 `json:"heartbeat_level"`
+	HeartbeatContext          map[string]interface{}                                   `json:"heartbeat_context"`
+	ConsoleOnly               bool                                                     `json:"console_only"`
+	AllowedContextKeys        []string                                                 `json:"allowed_context_keys"`
+	MaxContextKeys            int                                                      `json:"max_context_keys"`
+	Sinks                     []Sink                                                   `json:"-"`
+	Observer                  func(Event)                                              `json:"-"`
+	RetryQueueWatermarks      []int                                                    `json:"retry_queue_watermarks"`
+	RetryQueueWatermarkRatios []float64                                                `json:"retry_queue_watermark_ratios"`
+	TypeFormatters            map[reflect.Type]func(interface{}) interface{}           `json:"-"`
+	Enrichers                 []Enricher                                               `json:"-"`
+	MaxDetailBytes            int                                                      `json:"max_detail_bytes"`
+	ConsoleDetail             bool                                                     `json:"console_detail"`
+	MaxValueBytes             int                                                      `json:"max_value_bytes"`
+	IncludeSDKMeta            bool                                                     `json:"include_sdk_meta"`
+	RetryQueue                RetryQueue                                               `json:"-"`
+	SampleKey                 func(ctx context.Context, data *LogData) string          `json:"-"`
+	SampleRate                float64                                                  `json:"sample_rate"`
+	DebounceFlush             time.Duration                                            `json:"debounce_flush"`
+	CustomValidator           func(data *LogData) error                                `json:"-"`
+	OnCanceledContext         CanceledContextPolicy                                    `json:"on_canceled_context"`
+	ErrorThrottleWindow       time.Duration                                            `json:"error_throttle_window"`
+	ConsoleFormatByLevel      map[LogLevel]string                                      `json:"console_format_by_level"`
+	EnabledLevels             []LogLevel                                               `json:"enabled_levels"`
+	SampleRates               map[LogLevel]float64                                     `json:"sample_rates"`
+	AdaptiveSampling          *AdaptiveSampling                                        `json:"adaptive_sampling"`
+	StartupJitter             time.Duration                                            `json:"startup_jitter"`
+	Sequence                  bool                                                     `json:"sequence"`
+	Endpoints                 []WeightedEndpoint                                       `json:"endpoints"`
+	DetectContextOverrides    bool                                                     `json:"detect_context_overrides"`
+	MaxResponseBytes          int64                                                    `json:"max_response_bytes"`
+	MaxAttachmentBytes        int                                                      `json:"max_attachment_bytes"`
+	MaxEntryBytes             int                                                      `json:"max_entry_bytes"`
+	InstanceID                string                                                   `json:"instance_id"`
+	AutoRetry                 bool                                                     `json:"auto_retry"`
+	RetryInterval             time.Duration                                            `json:"retry_interval"`
+	BufferSize                int                                                      `json:"buffer_size"`
+	FlushInterval             time.Duration                                            `json:"flush_interval"`
+	DropWhenFull              bool                                                     `json:"drop_when_full"`
+	MaxRetries                int                                                      `json:"max_retries"`
+	RetryBaseDelay            time.Duration                                            `json:"retry_base_delay"`
+	Compress                  bool                                                     `json:"compress"`
+	HTTPClient                HTTPClient                                               `json:"-"`
+	RedactKeys                []string                                                 `json:"redact_keys"`
+	IncludeCaller             bool                                                     `json:"include_caller"`
+	CaptureStackTrace         bool                                                     `json:"capture_stack_trace"`
+	MaxStackTraceBytes        int                                                      `json:"max_stack_trace_bytes"`
+	MaxRetryQueueSize         int                                                      `json:"max_retry_queue_size"`
+	DropPolicy                DropPolicy                                               `json:"drop_policy"`
+	MinLevel                  LogLevel                                                 `json:"min_level"`
+	TraceContext              func(ctx context.Context) (traceID, spanID string)       `json:"-"`
+	ContextExtractors         map[string]func(ctx context.Context) (interface{}, bool) `json:"-"`
+	TruncateOversized         bool                                                     `json:"truncate_oversized"`
+	MaxMessageLength          int                                                      `json:"max_message_length"`
+	MaxContextBytes           int                                                      `json:"max_context_bytes"`
+	ConsoleFormat             string                                                   `json:"console_format"`
+	ConsoleTimeFormat         string                                                   `json:"console_time_format"`
+	ConsoleWriter             io.Writer                                                `json:"-"`
+	ConsoleColor              bool                                                     `json:"console_color"`
+	LogSummaryOnClose         bool                                                     `json:"log_summary_on_close"`
+	BackoffFunc               func(attempt int) time.Duration                          `json:"-"`
+	BatchBySource             bool                                                     `json:"batch_by_source"`
+	BatchTransformer          func([]LogData) []LogData                                `json:"-"`
+}
+
+// HTTPClient sends the logger's HTTP requests. *http.Client satisfies it;
+// set Options.HTTPClient to supply one with a custom transport, proxy or TLS
+// configuration, or a fake in tests.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Sink receives every log entry that passes validation, alongside the
+// CheckLogs API. Set Silent to stop sending to CheckLogs and only feed sinks.
+type Sink interface {
+	Write(ctx context.Context, data LogData) error
+}
+
+// SinkFlusher is implemented by sinks that buffer entries. Shutdown and
+// Close flush them once the retry queue has been flushed.
+type SinkFlusher interface {
+	Flush(ctx context.Context) error
+}
+
+// ContextSchema validates the context of a log entry. The value passed to
+// Validate is the context decoded from its JSON form (numbers as
+// json.Number), so a compiled schema from most JSON Schema libraries, such
+// as *jsonschema.Schema from santhosh-tekuri/jsonschema, satisfies it.
+type ContextSchema interface {
+	Validate(v interface{}) error
 }
 
 // Logger represents the CheckLogs logger
 type Logger struct {
-	apiKey     string
-	options    Options
-	httpClient *http.Client
-	retryQueue []LogData
-	mutex      sync.RWMutex
+	apiKey         string
+	options        Options
+	httpClient     HTTPClient
+	retryQueue     RetryQueue
+	console        *consoleWriter
+	urlCache       *urlCache
+	allowedKeys    map[string]struct{}
+	watermarks     *watermarkState
+	defaultContext map[string]interface{}
+	traceID        string
+	spanID         string
+	debouncer      *flushDebouncer
+	latency        *latencyReservoir
+	stats          *statsManager
+	limits         *logLimits
+	throttle       *errorThrottle
+	closed         *atomic.Bool
+	sampler        *levelSampler
+	seq            *atomic.Uint64
+	balancer       *endpointBalancer
+	retryWorker    *retryWorker
+	buffer         *asyncBuffer
+	redactKeys     map[string]struct{}
+	queueDropped   *atomic.Int64
+	levels         *levelFilter
+	started        time.Time
+	// root is the logger a child was derived from, which owns the
+	// background workers; nil for a logger created by NewLogger
+	root *Logger
 }
 
 // Timer represents a timing operation
 type Timer struct {
-	start   time.Time
-	name    string
-	message string
-	logger  *Logger
+	start    time.Time
+	name     string
+	message  string
+	logger   *Logger
+	memStats *runtime.MemStats
 }
 
 // Custom error types
@@ -73,19 +234,112 @@ type CheckLogsError struct {
 	Type    string `json:"type"`
 	Message string `json:"message"`
 	Code    int    `json:"code,omitempty"`
+	// Cause is the underlying error of a NetworkError, such as a
+	// context.DeadlineExceeded or a *url.Error
+	Cause error `json:"-"`
 }
 
+// Sentinel errors to match with errors.Is. They match any CheckLogsError of
+// the same type, whatever its message.
+var (
+	ErrRateLimited  = &CheckLogsError{Type: "RateLimitError", Message: "rate limit exceeded", Code: 429}
+	ErrUnauthorized = &CheckLogsError{Type: "AuthenticationError", Message: "Invalid API key", Code: 401}
+	ErrValidation   = &CheckLogsError{Type: "ValidationError", Message: "validation failed"}
+)
+
 func (e *CheckLogsError) Error() string {
 	return fmt.Sprintf("[%s] %s", e.Type, e.Message)
 }
 
+// Is reports whether target is a CheckLogsError of the same type, so
+// errors.Is(err, ErrRateLimited) matches every rate limit error
+func (e *CheckLogsError) Is(target error) bool {
+	t, ok := target.(*CheckLogsError)
+	return ok && t.Type == e.Type
+}
+
+// Unwrap returns the cause of the error, so errors.Is(err,
+// context.DeadlineExceeded) matches a request that timed out
+func (e *CheckLogsError) Unwrap() error {
+	return e.Cause
+}
+
+// IsTimeoutError reports whether a NetworkError was caused by a timeout,
+// either a context deadline or the HTTP client's Timeout
+func (e *CheckLogsError) IsTimeoutError() bool {
+	if e.Type != "NetworkError" || e.Cause == nil {
+		return false
+	}
+	if errors.Is(e.Cause, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(e.Cause, &netErr) && netErr.Timeout()
+}
+
+// CanceledContextPolicy decides what happens to a log sent with a context
+// that is already cancelled or past its deadline
+type CanceledContextPolicy int
+
+const (
+	// CanceledContextQueue queues the log for retry without attempting to send it
+	CanceledContextQueue CanceledContextPolicy = iota
+	// CanceledContextReturn drops the log and returns the context error
+	CanceledContextReturn
+)
+
+// SendOutcome describes what happened to a log entry after a send attempt
+type SendOutcome int
+
+const (
+	// Delivered means the log was accepted by the CheckLogs API
+	Delivered SendOutcome = iota
+	// Queued means the send failed but the log was added to the retry queue
+	Queued
+	// Dropped means the log was not sent and will not be retried
+	Dropped
+	// ValidationFailed means the log was rejected before any send attempt
+	ValidationFailed
+	// Buffered means the log was accepted into the BufferSize buffer and
+	// will be sent in a batch
+	Buffered
+)
+
+func (o SendOutcome) String() string {
+	switch o {
+	case Delivered:
+		return "delivered"
+	case Queued:
+		return "queued"
+	case Dropped:
+		return "dropped"
+	case ValidationFailed:
+		return "validation_failed"
+	case Buffered:
+		return "buffered"
+	default:
+		return "unknown"
+	}
+}
+
 // NewLogger creates a new CheckLogs logger
 func NewLogger(apiKey string, opts *Options) *Logger {
 	// Set default options
 	options := Options{
-		ConsoleOutput: true,
-		BaseURL:       DefaultURL,
-		Timeout:       30 * time.Second,
+		ConsoleOutput:      true,
+		BaseURL:            DefaultURL,
+		Timeout:            30 * time.Second,
+		BaseURLCacheTTL:    30 * time.Second,
+		MaxMessageLength:   DefaultMaxMessageLength,
+		MaxContextBytes:    DefaultMaxContextBytes,
+		MaxDetailBytes:     DefaultMaxDetailBytes,
+		MaxResponseBytes:   DefaultMaxResponseBytes,
+		MaxAttachmentBytes: DefaultMaxAttachmentBytes,
+		RetryBaseDelay:     DefaultRetryBaseDelay,
+		MaxStackTraceBytes: DefaultMaxStackTraceBytes,
+		ConsoleFormat:      ConsoleFormatText,
+		ConsoleTimeFormat:  DefaultConsoleTimeFormat,
+		ConsoleWriter:      os.Stdout,
 	}
 
 	// Override with provided options
@@ -107,30 +361,207 @@ func NewLogger(apiKey string, opts *Options) *Logger {
 		if opts.Timeout > 0 {
 			options.Timeout = opts.Timeout
 		}
+		options.ConsoleBuffered = opts.ConsoleBuffered
+		options.BaseURLResolver = opts.BaseURLResolver
+		if opts.BaseURLCacheTTL > 0 {
+			options.BaseURLCacheTTL = opts.BaseURLCacheTTL
+		}
+		options.ContextSchema = opts.ContextSchema
+		if opts.HeartbeatMessage != "" {
+			options.HeartbeatMessage = opts.HeartbeatMessage
+		}
+		if opts.HeartbeatLevel != "" {
+			options.HeartbeatLevel = opts.HeartbeatLevel
+		}
+		if opts.HeartbeatContext != nil {
+			options.HeartbeatContext = opts.HeartbeatContext
+		}
+		options.ConsoleOnly = opts.ConsoleOnly
+		if len(opts.AllowedContextKeys) > 0 {
+			options.AllowedContextKeys = opts.AllowedContextKeys
+		}
+		if opts.MaxContextKeys > 0 {
+			options.MaxContextKeys = opts.MaxContextKeys
+		}
+		options.Sinks = opts.Sinks
+		options.Observer = opts.Observer
+		if len(opts.RetryQueueWatermarks) > 0 {
+			options.RetryQueueWatermarks = opts.RetryQueueWatermarks
+		}
+		if len(opts.RetryQueueWatermarkRatios) > 0 {
+			options.RetryQueueWatermarkRatios = opts.RetryQueueWatermarkRatios
+		}
+		options.TypeFormatters = opts.TypeFormatters
+		options.Enrichers = opts.Enrichers
+		if opts.MaxDetailBytes > 0 {
+			options.MaxDetailBytes = opts.MaxDetailBytes
+		}
+		if opts.MaxMessageLength > 0 {
+			options.MaxMessageLength = opts.MaxMessageLength
+		}
+		if opts.MaxContextBytes > 0 {
+			options.MaxContextBytes = opts.MaxContextBytes
+		}
+		options.ConsoleDetail = opts.ConsoleDetail
+		if opts.MaxValueBytes > 0 {
+			options.MaxValueBytes = opts.MaxValueBytes
+		}
+		options.IncludeSDKMeta = opts.IncludeSDKMeta
+		options.RetryQueue = opts.RetryQueue
+		options.SampleKey = opts.SampleKey
+		options.SampleRate = opts.SampleRate
+		options.CustomValidator = opts.CustomValidator
+		options.OnCanceledContext = opts.OnCanceledContext
+		options.ConsoleFormatByLevel = opts.ConsoleFormatByLevel
+		if opts.ConsoleFormat != "" {
+			options.ConsoleFormat = opts.ConsoleFormat
+		}
+		if opts.ConsoleTimeFormat != "" {
+			options.ConsoleTimeFormat = opts.ConsoleTimeFormat
+		}
+		if opts.ConsoleWriter != nil {
+			options.ConsoleWriter = opts.ConsoleWriter
+		}
+		options.ConsoleColor = opts.ConsoleColor
+		options.EnabledLevels = opts.EnabledLevels
+		options.MinLevel = opts.MinLevel
+		options.TraceContext = opts.TraceContext
+		options.ContextExtractors = opts.ContextExtractors
+		options.TruncateOversized = opts.TruncateOversized
+		options.SampleRates = opts.SampleRates
+		options.AdaptiveSampling = opts.AdaptiveSampling
+		options.Sequence = opts.Sequence
+		options.Endpoints = opts.Endpoints
+		options.DetectContextOverrides = opts.DetectContextOverrides
+		if opts.MaxResponseBytes > 0 {
+			options.MaxResponseBytes = opts.MaxResponseBytes
+		}
+		if opts.MaxRetries > 0 {
+			options.MaxRetries = opts.MaxRetries
+		}
+		options.Compress = opts.Compress
+		options.HTTPClient = opts.HTTPClient
+		options.RedactKeys = opts.RedactKeys
+		if opts.MaxRetryQueueSize > 0 {
+			options.MaxRetryQueueSize = opts.MaxRetryQueueSize
+		}
+		options.DropPolicy = opts.DropPolicy
+		options.IncludeCaller = opts.IncludeCaller
+		options.CaptureStackTrace = opts.CaptureStackTrace
+		if opts.MaxStackTraceBytes > 0 {
+			options.MaxStackTraceBytes = opts.MaxStackTraceBytes
+		}
+		if opts.RetryBaseDelay > 0 {
+			options.RetryBaseDelay = opts.RetryBaseDelay
+		}
+		if opts.BufferSize > 0 {
+			options.BufferSize = opts.BufferSize
+		}
+		if opts.FlushInterval > 0 {
+			options.FlushInterval = opts.FlushInterval
+		}
+		options.DropWhenFull = opts.DropWhenFull
+		options.AutoRetry = opts.AutoRetry
+		if opts.RetryInterval > 0 {
+			options.RetryInterval = opts.RetryInterval
+		}
+		if opts.InstanceID != "" {
+			options.InstanceID = opts.InstanceID
+		}
+		if opts.MaxEntryBytes > 0 {
+			options.MaxEntryBytes = opts.MaxEntryBytes
+		}
+		if opts.MaxAttachmentBytes > 0 {
+			options.MaxAttachmentBytes = opts.MaxAttachmentBytes
+		}
+		if opts.StartupJitter > 0 {
+			options.StartupJitter = opts.StartupJitter
+		}
+		if opts.ErrorThrottleWindow > 0 {
+			options.ErrorThrottleWindow = opts.ErrorThrottleWindow
+		}
+		if opts.DebounceFlush > 0 {
+			options.DebounceFlush = opts.DebounceFlush
+		}
+		options.LogSummaryOnClose = opts.LogSummaryOnClose
+		options.BackoffFunc = opts.BackoffFunc
+		options.BatchBySource = opts.BatchBySource
+		options.BatchTransformer = opts.BatchTransformer
 	}
 
-	return &Logger{
-		apiKey:     apiKey,
-		options:    options,
-		httpClient: &http.Client{Timeout: options.Timeout},
-		retryQueue: make([]LogData, 0),
+	if options.InstanceID == "" {
+		options.InstanceID = os.Getenv(InstanceIDEnv)
+	}
+
+	var allowedKeys map[string]struct{}
+	if len(options.AllowedContextKeys) > 0 {
+		allowedKeys = make(map[string]struct{}, len(options.AllowedContextKeys))
+		for _, k := range options.AllowedContextKeys {
+			allowedKeys[k] = struct{}{}
+		}
 	}
+
+	retryQueue := options.RetryQueue
+	if retryQueue == nil {
+		retryQueue = newMemoryRetryQueue(options.MaxRetryQueueSize, options.DropPolicy)
+	}
+
+	// A custom client controls its own timeout
+	httpClient := options.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: options.Timeout}
+	}
+
+	logger := &Logger{
+		apiKey:       apiKey,
+		options:      options,
+		httpClient:   httpClient,
+		retryQueue:   retryQueue,
+		console:      newConsoleWriter(options.ConsoleWriter, options.ConsoleBuffered, options.ConsoleColor),
+		urlCache:     &urlCache{},
+		allowedKeys:  allowedKeys,
+		watermarks:   newWatermarkState(retryQueueWatermarks(options)),
+		latency:      newLatencyReservoir(),
+		stats:        newStatsManager(),
+		limits:       newLogLimits(options),
+		throttle:     newErrorThrottle(options.ErrorThrottleWindow),
+		closed:       &atomic.Bool{},
+		sampler:      newLevelSampler(options.SampleRates),
+		seq:          &atomic.Uint64{},
+		balancer:     newEndpointBalancer(options.Endpoints),
+		redactKeys:   newRedactSet(options.RedactKeys),
+		levels:       newLevelFilter(options.EnabledLevels, options.MinLevel),
+		queueDropped: &atomic.Int64{},
+		started:      time.Now(),
+	}
+	logger.debouncer = logger.newDebouncer()
+	if options.AdaptiveSampling != nil {
+		logger.sampler.startAdaptive(*options.AdaptiveSampling, logger.stats)
+	}
+	if options.AutoRetry {
+		logger.retryWorker = logger.startRetryWorker()
+	}
+	if options.BufferSize > 0 {
+		logger.buffer = newAsyncBuffer(logger)
+	}
+	logger.prepareDefaultContext()
+	return logger
 }
 
 // NewLoggerWithValidation creates a new CheckLogs logger and validates the API key
 func NewLoggerWithValidation(apiKey string, opts *Options) (*Logger, error) {
 	logger := NewLogger(apiKey, opts)
-	
+
 	// Valider la clé API si elle est fournie
 	if apiKey != "" {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
-		
+
 		if err := logger.ValidateAPIKey(ctx); err != nil {
 			return nil, fmt.Errorf("API key validation failed: %w", err)
 		}
 	}
-	
+
 	return logger, nil
 }
 
@@ -144,16 +575,76 @@ func CreateLoggerWithValidation(apiKey string) (*Logger, error) {
 	return NewLoggerWithValidation(apiKey, nil)
 }
 
+// urlCache holds the last base URL returned by Options.BaseURLResolver
+type urlCache struct {
+	mutex   sync.Mutex
+	url     string
+	expires time.Time
+}
+
+// baseURL returns the API base URL for a request, consulting the resolver
+// when one is configured
+func (l *Logger) baseURL(ctx context.Context) (string, error) {
+	if l.balancer != nil {
+		return l.balancer.next(), nil
+	}
+	if l.options.BaseURLResolver == nil {
+		return l.options.BaseURL, nil
+	}
+
+	l.urlCache.mutex.Lock()
+	defer l.urlCache.mutex.Unlock()
+
+	if l.urlCache.url != "" && time.Now().Before(l.urlCache.expires) {
+		return l.urlCache.url, nil
+	}
+
+	url, err := l.options.BaseURLResolver(ctx)
+	if err != nil {
+		return "", &CheckLogsError{Type: "ConfigurationError", Message: "Cannot resolve base URL: " + err.Error()}
+	}
+	if url == "" {
+		return "", &CheckLogsError{Type: "ConfigurationError", Message: "Base URL resolver returned an empty URL"}
+	}
+
+	l.urlCache.url = url
+	l.urlCache.expires = time.Now().Add(l.options.BaseURLCacheTTL)
+	return url, nil
+}
+
+// limitResponse caps how much of a response body can be read, protecting
+// the client from oversized responses
+func (l *Logger) limitResponse(resp *http.Response) {
+	resp.Body = http.MaxBytesReader(nil, resp.Body, l.options.MaxResponseBytes)
+}
+
+// decodeResponse decodes a JSON response body into out
+func (l *Logger) decodeResponse(resp *http.Response, out interface{}) error {
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return &CheckLogsError{Type: "APIError", Message: fmt.Sprintf("response too large (max %d bytes)", l.options.MaxResponseBytes), Code: resp.StatusCode}
+		}
+		return &CheckLogsError{Type: "SerializationError", Message: "Cannot decode response: " + err.Error()}
+	}
+	return nil
+}
+
 // ValidateAPIKey validates the API key by making a test request
 func (l *Logger) ValidateAPIKey(ctx context.Context) error {
 	if l.apiKey == "" {
 		return &CheckLogsError{Type: "ConfigurationError", Message: "API key is required"}
 	}
 
+	baseURL, err := l.baseURL(ctx)
+	if err != nil {
+		return err
+	}
+
 	// Test avec une requête de validation
-	req, err := http.NewRequestWithContext(ctx, "GET", l.options.BaseURL+"/api/validate", nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"/api/validate", nil)
 	if err != nil {
-		return &CheckLogsError{Type: "NetworkError", Message: "Cannot create validation request: " + err.Error()}
+		return &CheckLogsError{Type: "NetworkError", Message: "Cannot create validation request: " + err.Error(), Cause: err}
 	}
 
 	req.Header.Set("Authorization", "Bearer "+l.apiKey)
@@ -161,18 +652,19 @@ func (l *Logger) ValidateAPIKey(ctx context.Context) error {
 
 	resp, err := l.httpClient.Do(req)
 	if err != nil {
-		return &CheckLogsError{Type: "NetworkError", Message: "Cannot reach CheckLogs API: " + err.Error()}
+		return &CheckLogsError{Type: "NetworkError", Message: "Cannot reach CheckLogs API: " + err.Error(), Cause: err}
 	}
 	defer resp.Body.Close()
+	l.limitResponse(resp)
 
 	if resp.StatusCode == 401 {
 		return &CheckLogsError{Type: "AuthenticationError", Message: "Invalid API key", Code: 401}
 	}
-	
+
 	if resp.StatusCode == 403 {
 		return &CheckLogsError{Type: "AuthorizationError", Message: "API key does not have required permissions", Code: 403}
 	}
-	
+
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
 		return &CheckLogsError{Type: "APIError", Message: fmt.Sprintf("API validation failed (HTTP %d): %s", resp.StatusCode, string(body)), Code: resp.StatusCode}
@@ -197,8 +689,15 @@ func (l *Logger) GetStatus(ctx context.Context) (map[string]interface{}, error)
 		return status, nil
 	}
 
+	baseURL, err := l.baseURL(ctx)
+	if err != nil {
+		status["error"] = err.Error()
+		return status, nil
+	}
+	status["base_url"] = baseURL
+
 	// Test de connectivité
-	req, err := http.NewRequestWithContext(ctx, "GET", l.options.BaseURL+"/api/status", nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"/api/status", nil)
 	if err != nil {
 		status["error"] = "Cannot create request: " + err.Error()
 		return status, nil
@@ -213,6 +712,7 @@ func (l *Logger) GetStatus(ctx context.Context) (map[string]interface{}, error)
 		return status, nil
 	}
 	defer resp.Body.Close()
+	l.limitResponse(resp)
 
 	status["api_reachable"] = true
 
@@ -232,29 +732,99 @@ func (l *Logger) GetStatus(ctx context.Context) (map[string]interface{}, error)
 
 // validateLogData validates a log entry
 func (l *Logger) validateLogData(data *LogData) error {
+	limits := l.limits.get()
+
 	if data.Message == "" {
 		return &CheckLogsError{Type: "ValidationError", Message: "message is required"}
 	}
-	if len(data.Message) > 1024 {
-		return &CheckLogsError{Type: "ValidationError", Message: "message too long (max 1024 characters)"}
+	if len(data.Message) > limits.MaxMessageLength {
+		return &CheckLogsError{Type: "ValidationError", Message: fmt.Sprintf("message too long (max %d characters)", limits.MaxMessageLength)}
+	}
+	if data.Source != "" && len(data.Source) > limits.MaxSourceLength {
+		return &CheckLogsError{Type: "ValidationError", Message: fmt.Sprintf("source too long (max %d characters)", limits.MaxSourceLength)}
 	}
-	if data.Source != "" && len(data.Source) > 100 {
-		return &CheckLogsError{Type: "ValidationError", Message: "source too long (max 100 characters)"}
+	if len(data.Detail) > limits.MaxDetailBytes {
+		return &CheckLogsError{Type: "ValidationError", Message: fmt.Sprintf("detail too long (max %d bytes)", limits.MaxDetailBytes)}
+	}
+	if len(data.Context) > 0 && getContextSize(data.Context) > limits.MaxContextBytes {
+		return &CheckLogsError{Type: "ValidationError", Message: fmt.Sprintf("context too large (max %d bytes when serialized)", limits.MaxContextBytes)}
+	}
+	if limits.MaxContextKeys > 0 && len(data.Context) > limits.MaxContextKeys {
+		return &CheckLogsError{Type: "ValidationError", Message: fmt.Sprintf("context has too many keys (%d, max %d)", len(data.Context), limits.MaxContextKeys)}
+	}
+	if attachmentSize(data.Attachments) > l.options.MaxAttachmentBytes {
+		return &CheckLogsError{Type: "ValidationError", Message: fmt.Sprintf("attachments too large (max %d bytes)", l.options.MaxAttachmentBytes)}
+	}
+	if l.options.ContextSchema != nil {
+		if err := l.validateContextSchema(data.Context); err != nil {
+			return err
+		}
+	}
+	if l.options.CustomValidator != nil {
+		if err := l.options.CustomValidator(data); err != nil {
+			if _, ok := err.(*CheckLogsError); ok {
+				return err
+			}
+			return &CheckLogsError{Type: "ValidationError", Message: err.Error()}
+		}
+	}
+	if l.options.MaxEntryBytes > 0 {
+		if size := EstimateSize(*data); size > l.options.MaxEntryBytes {
+			return &CheckLogsError{Type: "ValidationError", Message: fmt.Sprintf("log too large (%d bytes, max %d)", size, l.options.MaxEntryBytes)}
+		}
 	}
 	return nil
 }
 
-// sendLog sends a log entry to CheckLogs
-func (l *Logger) sendLog(ctx context.Context, data LogData) error {
-	// Set defaults
-	if data.Timestamp.IsZero() {
-		data.Timestamp = time.Now()
+// validateContextSchema checks a context map against Options.ContextSchema
+func (l *Logger) validateContextSchema(context map[string]interface{}) error {
+	if context == nil {
+		context = map[string]interface{}{}
 	}
-	if data.Source == "" && l.options.Source != "" {
-		data.Source = l.options.Source
+
+	raw, err := json.Marshal(context)
+	if err != nil {
+		return &CheckLogsError{Type: "ValidationError", Message: "context is not serializable: " + err.Error()}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return &CheckLogsError{Type: "ValidationError", Message: "context is not serializable: " + err.Error()}
+	}
+
+	if err := l.options.ContextSchema.Validate(value); err != nil {
+		return &CheckLogsError{Type: "ValidationError", Message: "context does not match schema: " + err.Error()}
+	}
+	return nil
+}
+
+// buildLogData creates a log entry with the logger's defaults and merged context
+func (l *Logger) buildLogData(ctx context.Context, level LogLevel, message string, contexts ...map[string]interface{}) LogData {
+	return l.newLogData(ctx, level, message, false, contexts...)
+}
+
+// newLogData builds a log entry. A preview entry is not sent, so it shows
+// the next sequence number without taking it.
+func (l *Logger) newLogData(ctx context.Context, level LogLevel, message string, preview bool, contexts ...map[string]interface{}) LogData {
+	data := LogData{
+		Message:    message,
+		Level:      level,
+		Timestamp:  time.Now(),
+		Source:     l.options.Source,
+		UserID:     l.options.UserID,
+		InstanceID: l.options.InstanceID,
+		TraceID:    l.traceID,
+		SpanID:     l.spanID,
 	}
-	if data.UserID == nil && l.options.UserID != nil {
-		data.UserID = l.options.UserID
+
+	// Trace correlation from the call's context, over WithTrace IDs
+	if l.options.TraceContext != nil {
+		if traceID, spanID := l.options.TraceContext(ctx); traceID != "" {
+			data.TraceID = traceID
+			data.SpanID = spanID
+		}
 	}
 
 	// Add hostname
@@ -262,157 +832,593 @@ func (l *Logger) sendLog(ctx context.Context, data LogData) error {
 		data.Hostname = hostname
 	}
 
-	// Merge default context
-	if l.options.Context != nil {
-		if data.Context == nil {
-			data.Context = make(map[string]interface{})
+	// Sequence number to order logs with identical timestamps
+	if l.options.Sequence {
+		if preview {
+			data.Seq = l.seq.Load() + 1
+		} else {
+			data.Seq = l.seq.Add(1)
 		}
-		for k, v := range l.options.Context {
-			if _, exists := data.Context[k]; !exists {
+	}
+
+	// Merge contexts into a single allocation: the prepared default context
+	// first, then values extracted from ctx, then call contexts which take
+	// precedence
+	size := len(l.defaultContext) + len(l.options.ContextExtractors)
+	for _, callContext := range contexts {
+		size += len(callContext)
+	}
+	if size > 0 {
+		data.Context = make(map[string]interface{}, size)
+
+		// Remember which layer set each key to report overrides
+		var origins map[string]string
+		if l.options.DetectContextOverrides {
+			origins = make(map[string]string, size)
+		}
+
+		for k, v := range l.defaultContext {
+			data.Context[k] = v
+			if origins != nil {
+				origins[k] = "default context"
+			}
+		}
+		for k, extract := range l.options.ContextExtractors {
+			if !l.isAllowedKey(k) {
+				continue
+			}
+			if v, ok := extract(ctx); ok {
 				data.Context[k] = v
+				if origins != nil {
+					l.reportContextOverride(k, origins, "context extractor")
+				}
+			}
+		}
+		for i, callContext := range contexts {
+			for k, v := range callContext {
+				if l.isAllowedKey(k) {
+					data.Context[k] = v
+					if origins != nil {
+						l.reportContextOverride(k, origins, fmt.Sprintf("call context %d", i+1))
+					}
+				}
+			}
+		}
+	}
+
+	// Location of the user's log call
+	if l.options.IncludeCaller {
+		if caller, function, ok := callerInfo(); ok {
+			if data.Context == nil {
+				data.Context = make(map[string]interface{}, 2)
+			}
+			data.Context["caller"] = caller
+			data.Context["function"] = function
+		}
+	}
+
+	// Stack trace for errors only, lower levels skip the cost
+	if l.options.CaptureStackTrace && (level == Error || level == Critical) {
+		if data.Context == nil {
+			data.Context = make(map[string]interface{}, 1)
+		}
+		data.Context["stack_trace"] = stackTrace(l.options.MaxStackTraceBytes)
+	}
+
+	return data
+}
+
+// withDefaults completes a caller-built entry, as sent by LogBatch and
+// LogConfirmed, through newLogData, so it gets the same defaults, merged
+// context and sequence number as a logged message. Fields set on data are
+// kept and its context takes precedence over the logger's; the caller's map
+// is left untouched.
+func (l *Logger) withDefaults(ctx context.Context, data LogData) LogData {
+	entry := l.newLogData(ctx, data.Level, data.Message, false, data.Context)
+	entry.Detail = data.Detail
+	entry.Attachments = data.Attachments
+	if !data.Timestamp.IsZero() {
+		entry.Timestamp = data.Timestamp
+	}
+	if data.Source != "" {
+		entry.Source = data.Source
+	}
+	if data.UserID != nil {
+		entry.UserID = data.UserID
+	}
+	if data.InstanceID != "" {
+		entry.InstanceID = data.InstanceID
+	}
+	if data.Hostname != "" {
+		entry.Hostname = data.Hostname
+	}
+	if data.TraceID != "" {
+		entry.TraceID = data.TraceID
+		entry.SpanID = data.SpanID
+	}
+	return entry
+}
+
+// prepareLogData applies the logger's processing of context values to an
+// entry about to be sent: enrichers, the allowlist, redaction, type
+// formatters, value caps and TruncateOversized. Every send path calls it, so
+// an entry gets the same treatment whether it is logged, batched or
+// confirmed.
+func (l *Logger) prepareLogData(data *LogData) {
+	// Environment-specific enrichment
+	for _, enricher := range l.options.Enrichers {
+		enricher.Enrich(data)
+	}
+
+	// Keep allowed keys only, whoever set them
+	if l.allowedKeys != nil {
+		for k := range data.Context {
+			if !l.isAllowedKey(k) {
+				delete(data.Context, k)
 			}
 		}
 	}
 
+	// Scrub sensitive values
+	if len(data.Context) > 0 {
+		l.redactContext(data.Context)
+	}
+
+	// Apply custom formatters to context values
+	if len(l.options.TypeFormatters) > 0 {
+		for k, v := range data.Context {
+			data.Context[k] = l.formatValue(v)
+		}
+	}
+
+	// Cap individual context values
+	if l.options.MaxValueBytes > 0 {
+		for k, v := range data.Context {
+			data.Context[k] = capValue(v, l.options.MaxValueBytes)
+		}
+	}
+
+	// Shorten oversized fields so the entry passes validation
+	if l.options.TruncateOversized {
+		l.truncateOversized(data)
+	}
+}
+
+// getContextSize returns the serialized size of a context value in bytes
+func getContextSize(v interface{}) int {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return len(raw)
+}
+
+// capValue truncates a string, or replaces any other value, whose
+// serialized size exceeds maxBytes
+func capValue(v interface{}, maxBytes int) interface{} {
+	size := getContextSize(v)
+	if size <= maxBytes {
+		return v
+	}
+	if str, ok := v.(string); ok && maxBytes > 2 {
+		// Leave room for the quotes around the serialized string
+		return truncateString(str, maxBytes-2)
+	}
+	return fmt.Sprintf("<oversized %d bytes>", size)
+}
+
+// truncateString shortens s to at most maxBytes bytes without splitting a
+// UTF-8 character, marking the cut with "..."
+func truncateString(s string, maxBytes int) string {
+	if len(s) <= maxBytes {
+		return s
+	}
+	if maxBytes <= 3 {
+		return s[:maxBytes]
+	}
+	cut := maxBytes - 3
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "..."
+}
+
+// isAllowedKey reports whether a context key passes Options.AllowedContextKeys
+func (l *Logger) isAllowedKey(key string) bool {
+	if l.allowedKeys == nil {
+		return true
+	}
+	_, allowed := l.allowedKeys[key]
+	return allowed
+}
+
+// prepareDefaultContext copies the default context once, dropping keys
+// outside the allowlist, so buildLogData does not redo that work per log
+func (l *Logger) prepareDefaultContext() {
+	l.defaultContext = nil
+
+	defaults := l.options.Context
+	if l.options.IncludeSDKMeta {
+		defaults = make(map[string]interface{}, len(l.options.Context)+2)
+		defaults["sdk_version"] = Version
+		defaults["go_version"] = runtime.Version()
+		for k, v := range l.options.Context {
+			defaults[k] = v
+		}
+	}
+
+	if len(defaults) == 0 {
+		return
+	}
+	l.defaultContext = make(map[string]interface{}, len(defaults))
+	for k, v := range defaults {
+		if l.isAllowedKey(k) {
+			l.defaultContext[k] = v
+		}
+	}
+}
+
+// formatValue applies Options.TypeFormatters to a context value, descending
+// into nested maps and slices without modifying the caller's values
+func (l *Logger) formatValue(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	if formatter, ok := l.options.TypeFormatters[reflect.TypeOf(v)]; ok {
+		return formatter(v)
+	}
+
+	switch value := v.(type) {
+	case map[string]interface{}:
+		formatted := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			formatted[k] = l.formatValue(item)
+		}
+		return formatted
+	case []interface{}:
+		formatted := make([]interface{}, len(value))
+		for i, item := range value {
+			formatted[i] = l.formatValue(item)
+		}
+		return formatted
+	default:
+		return v
+	}
+}
+
+// SampleByKey deterministically decides whether to keep logs for key, so
+// the same key always gets the same decision for a given rate, across calls
+// and processes. rate is the fraction of keys kept, from 0 to 1.
+func (l *Logger) SampleByKey(key string, rate float64) bool {
+	if rate >= 1 {
+		return true
+	}
+	if rate <= 0 {
+		return false
+	}
+	hash := fnv.New64a()
+	hash.Write([]byte(key))
+	return float64(hash.Sum64())/float64(math.MaxUint64) < rate
+}
+
+// sendLog sends a log entry to CheckLogs
+func (l *Logger) sendLog(ctx context.Context, data LogData, opts ...callOption) (outcome SendOutcome, err error) {
+	call := newCallOptions(opts)
+
+	if l.closed.Load() && !call.replay && !call.closing {
+		return Dropped, ErrClientClosed
+	}
+
+	// Level filtering and sampling
+	if !call.replay && (!l.isLevelEnabled(data.Level) || !l.sampler.keep(data.Level)) {
+		return Dropped, nil
+	}
+
+	// Enrich and scrub the entry; replays were prepared when first logged
+	if !call.replay {
+		l.prepareLogData(&data)
+	}
+
+	// Key-based sampling
+	if l.options.SampleKey != nil {
+		if key := l.options.SampleKey(ctx, &data); key != "" && !l.SampleByKey(key, l.options.SampleRate) {
+			return Dropped, nil
+		}
+	}
+
 	// Validate
 	if err := l.validateLogData(&data); err != nil {
-		return err
+		return ValidationFailed, err
+	}
+
+	// Count each log once, not again when replayed from the retry queue, and
+	// throttle the delivery errors returned to the caller
+	if !call.replay {
+		l.stats.IncrementLogs(data.Level)
+		defer func() {
+			if err != nil {
+				l.stats.IncrementErrors()
+				if l.throttle != nil {
+					err = l.throttle.filter(err)
+				}
+			}
+		}()
 	}
 
 	// Console output
-	if l.options.ConsoleOutput && !l.options.Silent {
-		fmt.Printf("[%s] %s: %s\n", data.Timestamp.Format("15:04:05"), data.Level, data.Message)
+	if l.options.ConsoleOutput && !l.options.Silent && !call.noConsole {
+		l.console.WriteString(l.formatConsole(data))
+	}
+
+	// Don't attempt delivery on a context that is already done
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		if l.options.OnCanceledContext == CanceledContextReturn && !call.replay {
+			return Dropped, ctxErr
+		}
+		return l.addToRetryQueue(data), ctxErr
+	}
+
+	// Forward to additional sinks, once: not again when replayed
+	if !call.replay {
+		for _, sink := range l.options.Sinks {
+			if err := sink.Write(ctx, data); err != nil && !l.options.Silent {
+				l.console.WriteString(fmt.Sprintf("[CHECKLOGS ERROR] sink: %s\n", err.Error()))
+			}
+		}
 	}
 
 	// Skip HTTP request if no API key
 	if l.apiKey == "" {
+		// Local development without credentials: console output only
+		if l.options.ConsoleOnly {
+			return Dropped, nil
+		}
+
 		err := &CheckLogsError{Type: "ConfigurationError", Message: "API key is required"}
 		// Afficher l'erreur même en mode console
 		if !l.options.Silent {
-			fmt.Printf("[CHECKLOGS ERROR] %s\n", err.Message)
+			l.console.WriteString(fmt.Sprintf("[CHECKLOGS ERROR] %s\n", err.Message))
 		}
-		return err
+		return Dropped, err
 	}
 
 	// Skip HTTP request in silent mode
 	if l.options.Silent {
-		return nil
+		return Dropped, nil
+	}
+
+	// Hand the log to the background sender in buffered mode. Logs with
+	// attachments are sent directly, as the batch endpoint takes JSON only.
+	if l.buffer != nil && !call.replay && len(data.Attachments) == 0 {
+		return l.buffer.enqueue(ctx, data)
 	}
 
-	// Prepare JSON
-	jsonData, err := json.Marshal(data)
+	// Prepare the body, multipart when the entry has attachments
+	body, contentType, err := encodeLogBody(data)
 	if err != nil {
-		return &CheckLogsError{Type: "SerializationError", Message: err.Error()}
+		return Dropped, &CheckLogsError{Type: "SerializationError", Message: err.Error()}
+	}
+	body, contentEncoding := l.compressBody(body)
+
+	// Send, retrying transient failures in-process up to MaxRetries times
+	// before falling back to the retry queue. Replays are attempted once.
+	for attempt := 1; ; attempt++ {
+		shouldRetry, err := l.postLog(ctx, body, contentType, contentEncoding)
+		if err == nil {
+			l.stats.AddBytes(len(body))
+			return Delivered, nil
+		}
+		if !shouldRetry {
+			// Show critical errors even in console mode
+			if e, ok := err.(*CheckLogsError); ok && (e.Type == "AuthenticationError" || e.Type == "AuthorizationError") && !l.options.Silent {
+				l.console.WriteString(fmt.Sprintf("[CHECKLOGS ERROR] %s\n", e.Message))
+			}
+			return Dropped, err
+		}
+		if call.replay || attempt > l.options.MaxRetries {
+			return l.addToRetryQueue(data), err
+		}
+
+		timer := time.NewTimer(l.retryDelay(attempt, l.options.RetryBaseDelay))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return l.addToRetryQueue(data), err
+		case <-timer.C:
+		}
+	}
+}
+
+// postLog makes one request to /api/logs and reports whether a failure is
+// worth retrying
+func (l *Logger) postLog(ctx context.Context, body []byte, contentType, contentEncoding string) (bool, error) {
+	baseURL, err := l.baseURL(ctx)
+	if err != nil {
+		return true, err
 	}
 
 	// Create request
-	req, err := http.NewRequestWithContext(ctx, "POST", l.options.BaseURL+"/api/logs", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/api/logs", bytes.NewBuffer(body))
 	if err != nil {
-		l.addToRetryQueue(data)
-		return &CheckLogsError{Type: "NetworkError", Message: err.Error()}
+		return true, &CheckLogsError{Type: "NetworkError", Message: err.Error(), Cause: err}
 	}
 
 	// Set headers
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", contentType)
+	if contentEncoding != "" {
+		req.Header.Set("Content-Encoding", contentEncoding)
+	}
 	req.Header.Set("Authorization", "Bearer "+l.apiKey)
 	req.Header.Set("User-Agent", "CheckLogs-Go-SDK/"+Version)
 
 	// Send request
+	start := time.Now()
 	resp, err := l.httpClient.Do(req)
 	if err != nil {
-		l.addToRetryQueue(data)
-		return &CheckLogsError{Type: "NetworkError", Message: err.Error()}
+		l.reportEndpoint(baseURL, false)
+		return true, &CheckLogsError{Type: "NetworkError", Message: err.Error(), Cause: err}
 	}
 	defer resp.Body.Close()
+	l.limitResponse(resp)
+	l.latency.record(time.Since(start))
+	l.reportEndpoint(baseURL, resp.StatusCode != 429 && resp.StatusCode < 500)
 
 	// Handle response with improved error handling
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		
-		var errType string
-		var shouldRetry bool
-		
-		switch resp.StatusCode {
-		case 401:
-			errType = "AuthenticationError"
-			shouldRetry = false
-		case 403:
-			errType = "AuthorizationError"
-			shouldRetry = false
-		case 429:
-			errType = "RateLimitError"
+		err, shouldRetry := statusError(resp.StatusCode, body)
+		return shouldRetry, err
+	}
+	return false, nil
+}
+
+// statusError converts an error response of the logs endpoints into a
+// CheckLogsError and reports whether the request is worth retrying
+func statusError(code int, body []byte) (*CheckLogsError, bool) {
+	var errType string
+	var shouldRetry bool
+
+	switch code {
+	case 401:
+		errType = "AuthenticationError"
+		shouldRetry = false
+	case 403:
+		errType = "AuthorizationError"
+		shouldRetry = false
+	case 429:
+		errType = "RateLimitError"
+		shouldRetry = true
+	case 400:
+		errType = "ValidationError"
+		shouldRetry = false
+	default:
+		if code >= 500 {
+			errType = "ServerError"
 			shouldRetry = true
-		case 400:
-			errType = "ValidationError"
+		} else {
+			errType = "ClientError"
 			shouldRetry = false
-		default:
-			if resp.StatusCode >= 500 {
-				errType = "ServerError"
-				shouldRetry = true
-			} else {
-				errType = "ClientError"
-				shouldRetry = false
-			}
-		}
-
-		err := &CheckLogsError{
-			Type:    errType,
-			Message: fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body)),
-			Code:    resp.StatusCode,
 		}
+	}
 
-		// Retry only on certain errors
-		if shouldRetry {
-			l.addToRetryQueue(data)
-		}
+	return &CheckLogsError{
+		Type:    errType,
+		Message: fmt.Sprintf("HTTP %d: %s", code, string(body)),
+		Code:    code,
+	}, shouldRetry
+}
 
-		// Show critical errors even in console mode
-		if (errType == "AuthenticationError" || errType == "AuthorizationError") && !l.options.Silent {
-			fmt.Printf("[CHECKLOGS ERROR] %s\n", err.Message)
+// addToRetryQueue adds a log to the retry queue, reporting Dropped when the
+// queue refuses it
+func (l *Logger) addToRetryQueue(data LogData) SendOutcome {
+	if err := l.retryQueue.Add(data); err != nil {
+		l.queueDropped.Add(1)
+		if !l.options.Silent {
+			l.console.WriteString(fmt.Sprintf("[CHECKLOGS ERROR] retry queue: %s\n", err.Error()))
 		}
-
-		return err
+		return Dropped
 	}
 
-	return nil
+	l.reportRetryQueueWatermarks(l.retryQueue.Len())
+	if l.debouncer != nil {
+		l.debouncer.trigger()
+	}
+	return Queued
 }
 
-// addToRetryQueue adds a log to the retry queue
-func (l *Logger) addToRetryQueue(data LogData) {
-	l.mutex.Lock()
-	defer l.mutex.Unlock()
-	l.retryQueue = append(l.retryQueue, data)
+// RetryQueueStatus returns the size of the retry queue and how many logs it
+// has lost: rejected when full, or evicted by the DropOldest policy of the
+// bounded in-memory queue
+func (l *Logger) RetryQueueStatus() RetryQueueStatus {
+	status := RetryQueueStatus{
+		Size:    l.retryQueue.Len(),
+		MaxSize: l.options.MaxRetryQueueSize,
+		Dropped: l.queueDropped.Load(),
+	}
+	if q, ok := l.retryQueue.(*memoryRetryQueue); ok {
+		status.Dropped += q.evicted.Load()
+	} else {
+		status.MaxSize = 0
+	}
+	return status
 }
 
 // GetRetryQueueSize returns the number of logs in the retry queue
 func (l *Logger) GetRetryQueueSize() int {
-	l.mutex.RLock()
-	defer l.mutex.RUnlock()
-	return len(l.retryQueue)
+	return l.retryQueue.Len()
+}
+
+// drainRetryQueue atomically removes and returns all logs in the retry queue
+func (l *Logger) drainRetryQueue() []LogData {
+	return l.retryQueue.Drain()
 }
 
 // FlushRetryQueue attempts to send all logs in the retry queue
 func (l *Logger) FlushRetryQueue(ctx context.Context) int {
-	l.mutex.Lock()
-	queue := make([]LogData, len(l.retryQueue))
-	copy(queue, l.retryQueue)
-	l.retryQueue = l.retryQueue[:0] // Clear queue
-	l.mutex.Unlock()
+	return l.FlushDetailed(ctx).Succeeded
+}
+
+// FlushResult reports the outcome of FlushDetailed
+type FlushResult struct {
+	Succeeded int
+	Failed    int
+	// Requeued counts the failed logs put back in the retry queue after a
+	// transient failure; the others were dropped
+	Requeued int
+	Errors   []error
+}
+
+// FlushDetailed attempts to send every log in the retry queue once and
+// reports the outcome of each. Logs that fail with a transient error, such
+// as a network error, a 429 or a 5xx, are queued again; the others are
+// dropped and their errors returned.
+func (l *Logger) FlushDetailed(ctx context.Context) FlushResult {
+	var result FlushResult
+	for _, data := range l.drainRetryQueue() {
+		outcome, err := l.sendLog(ctx, data, replay())
+		if err == nil {
+			result.Succeeded++
+			continue
+		}
+		result.Failed++
+		if outcome == Queued {
+			result.Requeued++
+		}
+		result.Errors = append(result.Errors, err)
+	}
+	l.rearmRetryQueueWatermarks(l.retryQueue.Len())
+	return result
+}
 
-	success := 0
-	for _, data := range queue {
-		if err := l.sendLog(ctx, data); err == nil {
-			success++
+// TransferQueueTo moves all logs in the retry queue to dst's retry queue and
+// returns how many were moved. Use it to hand a stuck logger's backlog to a
+// freshly configured one.
+func (l *Logger) TransferQueueTo(dst *Logger) int {
+	if dst == nil || dst == l {
+		return 0
+	}
+
+	moved := 0
+	for _, data := range l.drainRetryQueue() {
+		if dst.addToRetryQueue(data) == Queued {
+			moved++
 		}
 	}
-	return success
+	l.rearmRetryQueueWatermarks(l.retryQueue.Len())
+	return moved
+}
+
+// FlushConsole writes out any buffered console output. Call it before the
+// process exits when ConsoleBuffered is enabled.
+func (l *Logger) FlushConsole() error {
+	return l.console.Flush()
 }
 
 // ClearRetryQueue clears the retry queue
 func (l *Logger) ClearRetryQueue() {
-	l.mutex.Lock()
-	defer l.mutex.Unlock()
-	l.retryQueue = l.retryQueue[:0]
+	l.retryQueue.Clear()
+
+	l.rearmRetryQueueWatermarks(0)
 }
 
 // Log methods for different levels
@@ -442,26 +1448,85 @@ func (l *Logger) Critical(ctx context.Context, message string, context ...map[st
 	return l.log(ctx, Critical, message, context...)
 }
 
-// log is the internal logging method
-func (l *Logger) log(ctx context.Context, level LogLevel, message string, contexts ...map[string]interface{}) error {
-	data := LogData{
-		Message: message,
-		Level:   level,
+// ErrorWithDetail logs an error with a short, scannable message and long
+// multiline detail such as a stack trace, which has its own higher size limit
+func (l *Logger) ErrorWithDetail(ctx context.Context, message, detail string, context ...map[string]interface{}) error {
+	data := l.buildLogData(ctx, Error, message, context...)
+	data.Detail = detail
+	_, err := l.sendLog(ctx, data)
+	return err
+}
+
+// LogAndReturn logs err at level and returns it unchanged, for the common
+// "log and return" pattern:
+//
+//	return logger.LogAndReturn(ctx, checklogs.Error, err, nil)
+//
+// The context gets the error's type and, for wrapped errors, the types of
+// the chain. A nil err is not logged.
+func (l *Logger) LogAndReturn(ctx context.Context, level LogLevel, err error, fields map[string]interface{}) error {
+	if err == nil {
+		return nil
 	}
 
-	// Merge contexts
-	if len(contexts) > 0 {
-		data.Context = make(map[string]interface{})
-		for _, ctx := range contexts {
-			if ctx != nil {
-				for k, v := range ctx {
-					data.Context[k] = v
-				}
-			}
+	errContext := map[string]interface{}{
+		"error_type": fmt.Sprintf("%T", err),
+	}
+	if inner := errors.Unwrap(err); inner != nil {
+		chain := []string{fmt.Sprintf("%T", err)}
+		for ; inner != nil; inner = errors.Unwrap(inner) {
+			chain = append(chain, fmt.Sprintf("%T", inner))
 		}
+		errContext["error_chain"] = chain
 	}
 
-	return l.sendLog(ctx, data)
+	message := truncateString(err.Error(), l.limits.get().MaxMessageLength)
+	l.log(ctx, level, message, errContext, fields)
+	return err
+}
+
+// LogResult logs a message at the given level and reports whether it was
+// delivered, queued for retry, dropped or rejected by validation
+func (l *Logger) LogResult(ctx context.Context, level LogLevel, message string, context ...map[string]interface{}) (SendOutcome, error) {
+	return l.logResult(ctx, level, message, nil, context...)
+}
+
+// PreviewPayload returns the exact JSON that would be sent for a log, after
+// default context, enrichers, allowlist, redaction, value formatting and
+// truncation are applied, without sending anything or taking a sequence
+// number. When the log would be rejected locally, the payload is returned
+// along with the ValidationError.
+func (l *Logger) PreviewPayload(level LogLevel, message string, logContext map[string]interface{}) ([]byte, error) {
+	data := l.newLogData(context.Background(), level, message, true, logContext)
+	l.prepareLogData(&data)
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, &CheckLogsError{Type: "SerializationError", Message: err.Error()}
+	}
+	return payload, l.validateLogData(&data)
+}
+
+// EstimateSize returns the size in bytes of the request body that would be
+// sent for a log entry, including the multipart framing of attachments.
+// Entries that cannot be serialized report 0.
+func EstimateSize(data LogData) int {
+	body, _, err := encodeLogBody(data)
+	if err != nil {
+		return 0
+	}
+	return len(body)
+}
+
+// log is the internal logging method
+func (l *Logger) log(ctx context.Context, level LogLevel, message string, contexts ...map[string]interface{}) error {
+	_, err := l.logResult(ctx, level, message, nil, contexts...)
+	return err
+}
+
+// logResult builds a log entry and sends it with the given call options,
+// returning the send outcome
+func (l *Logger) logResult(ctx context.Context, level LogLevel, message string, opts []callOption, contexts ...map[string]interface{}) (SendOutcome, error) {
+	return l.sendLog(ctx, l.buildCallLogData(ctx, level, message, contexts, opts), opts...)
 }
 
 // Child creates a child logger with additional context
@@ -486,12 +1551,43 @@ func (l *Logger) Child(context map[string]interface{}) *Logger {
 	childOptions := l.options
 	childOptions.Context = newContext
 
-	return &Logger{
-		apiKey:     l.apiKey,
-		options:    childOptions,
-		httpClient: l.httpClient,
-		retryQueue: make([]LogData, 0),
+	child := &Logger{
+		apiKey:       l.apiKey,
+		options:      childOptions,
+		httpClient:   l.httpClient,
+		retryQueue:   l.retryQueue,
+		console:      l.console,
+		urlCache:     l.urlCache,
+		allowedKeys:  l.allowedKeys,
+		watermarks:   l.watermarks,
+		debouncer:    l.debouncer,
+		latency:      l.latency,
+		stats:        l.stats,
+		limits:       l.limits,
+		throttle:     l.throttle,
+		closed:       l.closed,
+		sampler:      l.sampler,
+		seq:          l.seq,
+		balancer:     l.balancer,
+		buffer:       l.buffer,
+		redactKeys:   l.redactKeys,
+		levels:       l.levels,
+		traceID:      l.traceID,
+		spanID:       l.spanID,
+		queueDropped: l.queueDropped,
+		root:         l.owner(),
 	}
+	child.prepareDefaultContext()
+	return child
+}
+
+// WithTrace creates a child logger that stamps the given trace and span IDs
+// on every log, for manual correlation without a tracing library
+func (l *Logger) WithTrace(traceID, spanID string) *Logger {
+	child := l.Child(nil)
+	child.traceID = traceID
+	child.spanID = spanID
+	return child
 }
 
 // Time creates a timer for measuring execution time
@@ -504,17 +1600,97 @@ func (l *Logger) Time(name, message string) *Timer {
 	}
 }
 
-// End ends the timer and logs the duration
+// TimeWithMemStats creates a timer that also reports memory allocation and
+// GC deltas between start and end. Reading memory stats briefly stops the
+// world, so use it to investigate specific operations rather than on hot paths.
+func (l *Logger) TimeWithMemStats(name, message string) *Timer {
+	timer := l.Time(name, message)
+	timer.memStats = &runtime.MemStats{}
+	runtime.ReadMemStats(timer.memStats)
+	timer.start = time.Now()
+	return timer
+}
+
+// TimeFunc starts a timer and returns the function that ends it, to time
+// a whole function in one line:
+//
+//	defer logger.TimeFunc("import", "Importing CSV")()
+//
+// Use TimeFuncRecover to also log panics.
+func (l *Logger) TimeFunc(name, message string) func() {
+	timer := l.Time(name, message)
+	return func() {
+		timer.End()
+	}
+}
+
+// TimeFuncRecover is like TimeFunc, but if the function panics, the panic
+// is logged at the Critical level with the duration so far, then the panic
+// resumes:
+//
+//	defer logger.TimeFuncRecover("import", "Importing CSV")()
+func (l *Logger) TimeFuncRecover(name, message string) func() {
+	timer := l.Time(name, message)
+	return func() {
+		if r := recover(); r != nil {
+			timer.end(context.Background(), Critical, fmt.Errorf("panic: %v", r))
+			panic(r)
+		}
+		timer.End()
+	}
+}
+
+// End ends the timer and logs the duration. The log is sent with a
+// background context; use EndCtx to tie it to a request.
 func (t *Timer) End() time.Duration {
+	return t.EndCtx(context.Background())
+}
+
+// EndCtx ends the timer and logs the duration using ctx, so cancellation and
+// context-derived fields apply to the emitted log
+func (t *Timer) EndCtx(ctx context.Context) time.Duration {
+	return t.end(ctx, Info, nil)
+}
+
+// EndWithError ends the timer of an operation that can fail. A non-nil err
+// is logged at the Error level with an error field, otherwise the duration
+// is logged at the Info level like End.
+func (t *Timer) EndWithError(err error) time.Duration {
+	if err == nil {
+		return t.end(context.Background(), Info, nil)
+	}
+	return t.end(context.Background(), Error, err)
+}
+
+// EndAt ends the timer and logs the duration at level
+func (t *Timer) EndAt(level LogLevel) time.Duration {
+	return t.end(context.Background(), level, nil)
+}
+
+// end logs the duration at level, as a failure when err is not nil
+func (t *Timer) end(ctx context.Context, level LogLevel, err error) time.Duration {
 	duration := time.Since(t.start)
 
-	ctx := context.Background()
 	context := map[string]interface{}{
 		"operation":   t.name,
 		"duration_ms": duration.Milliseconds(),
 	}
 
-	t.logger.Info(ctx, fmt.Sprintf("%s completed in %v", t.message, duration), context)
+	if t.memStats != nil {
+		var end runtime.MemStats
+		runtime.ReadMemStats(&end)
+		context["heap_alloc_delta_bytes"] = int64(end.HeapAlloc) - int64(t.memStats.HeapAlloc)
+		context["total_alloc_delta_bytes"] = end.TotalAlloc - t.memStats.TotalAlloc
+		context["mallocs_delta"] = end.Mallocs - t.memStats.Mallocs
+		context["gc_count_delta"] = end.NumGC - t.memStats.NumGC
+	}
+
+	message := fmt.Sprintf("%s completed in %v", t.message, duration)
+	if err != nil {
+		message = fmt.Sprintf("%s failed after %v", t.message, duration)
+		context["error"] = err.Error()
+	}
+	t.logger.log(ctx, level, message, context)
 
 	return duration
 }
@@ -541,4 +1717,4 @@ func ParseLevel(s string) (LogLevel, error) {
 		return level, nil
 	}
 	return "", &CheckLogsError{Type: "ValidationError", Message: "invalid log level: " + s}
-}
\ No newline at end of file
+}
@@ -0,0 +1,274 @@
+package checklogs
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultFlushInterval is the default interval at which buffered logs are
+// sent
+const DefaultFlushInterval = time.Second
+
+// asyncBatchSize is the number of buffered logs that triggers a send
+// without waiting for the flush interval
+const asyncBatchSize = 100
+
+// ErrBufferFull is returned for logs dropped because the buffer is full and
+// DropWhenFull is set
+var ErrBufferFull = &CheckLogsError{Type: "BufferFullError", Message: "log buffer is full"}
+
+// flushRequest asks the buffer worker to send everything buffered within
+// ctx, and is acknowledged by closing done once undelivered is set
+type flushRequest struct {
+	ctx  context.Context
+	done chan struct{}
+	// undelivered counts the logs that were neither sent nor queued for
+	// retry
+	undelivered int
+}
+
+// asyncBuffer queues logs for a background worker that sends them through
+// the batch endpoint
+type asyncBuffer struct {
+	logger       *Logger
+	entries      chan LogData
+	flushes      chan *flushRequest
+	stops        chan *flushRequest
+	ctx          context.Context
+	cancel       context.CancelFunc
+	done         chan struct{}
+	interval     time.Duration
+	dropWhenFull bool
+}
+
+// newAsyncBuffer starts the buffer worker of a logger
+func newAsyncBuffer(l *Logger) *asyncBuffer {
+	interval := l.options.FlushInterval
+	if interval <= 0 {
+		interval = DefaultFlushInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &asyncBuffer{
+		logger:       l,
+		entries:      make(chan LogData, l.options.BufferSize),
+		flushes:      make(chan *flushRequest),
+		stops:        make(chan *flushRequest),
+		ctx:          ctx,
+		cancel:       cancel,
+		done:         make(chan struct{}),
+		interval:     interval,
+		dropWhenFull: l.options.DropWhenFull,
+	}
+	go b.run()
+	return b
+}
+
+// enqueue buffers a log, blocking while the buffer is full unless
+// DropWhenFull is set. Logs that cannot be buffered because ctx is done or
+// the worker has stopped go to the retry queue.
+func (b *asyncBuffer) enqueue(ctx context.Context, data LogData) (SendOutcome, error) {
+	select {
+	case b.entries <- data:
+		return Buffered, nil
+	default:
+	}
+	if b.dropWhenFull {
+		return Dropped, ErrBufferFull
+	}
+
+	select {
+	case b.entries <- data:
+		return Buffered, nil
+	case <-ctx.Done():
+		return b.logger.addToRetryQueue(data), ctx.Err()
+	case <-b.done:
+		return b.logger.addToRetryQueue(data), nil
+	}
+}
+
+// run collects buffered logs and sends them in batches every interval, or
+// as soon as a full batch is ready. With StartupJitter, the interval starts
+// after a random delay so instances started together do not flush in
+// lockstep.
+func (b *asyncBuffer) run() {
+	defer close(b.done)
+	defer b.cancel()
+
+	start := time.NewTimer(b.logger.startupJitter())
+	defer start.Stop()
+	var ticker *time.Ticker
+	var ticks <-chan time.Time
+	defer func() {
+		if ticker != nil {
+			ticker.Stop()
+		}
+	}()
+
+	batch := make([]LogData, 0, asyncBatchSize)
+	send := func(ctx context.Context) int {
+		if len(batch) == 0 {
+			return 0
+		}
+		undelivered := b.send(ctx, batch)
+		batch = make([]LogData, 0, asyncBatchSize)
+		return undelivered
+	}
+	add := func(ctx context.Context, data LogData) int {
+		batch = append(batch, data)
+		if len(batch) >= asyncBatchSize {
+			return send(ctx)
+		}
+		return 0
+	}
+	drain := func(ctx context.Context) int {
+		undelivered := 0
+		for {
+			select {
+			case data := <-b.entries:
+				undelivered += add(ctx, data)
+			default:
+				return undelivered + send(ctx)
+			}
+		}
+	}
+
+	for {
+		select {
+		case data := <-b.entries:
+			add(b.ctx, data)
+		case <-start.C:
+			ticker = time.NewTicker(b.interval)
+			ticks = ticker.C
+		case <-ticks:
+			send(b.ctx)
+		case req := <-b.flushes:
+			req.undelivered = drain(req.ctx)
+			close(req.done)
+		case req := <-b.stops:
+			req.undelivered = drain(req.ctx)
+			close(req.done)
+			return
+		}
+	}
+}
+
+// send posts the buffered logs, after BatchTransformer, in one batch per
+// source with BatchBySource, and returns the number of logs neither sent
+// nor queued for retry. Once ctx is done the logs go straight to the retry
+// queue.
+func (b *asyncBuffer) send(ctx context.Context, batch []LogData) int {
+	if transform := b.logger.options.BatchTransformer; transform != nil {
+		if batch = transform(batch); len(batch) == 0 {
+			return 0
+		}
+	}
+	if ctx.Err() != nil {
+		for _, data := range batch {
+			b.logger.addToRetryQueue(data)
+		}
+		return 0
+	}
+	if !b.logger.options.BatchBySource {
+		return b.post(ctx, batch)
+	}
+	undelivered := 0
+	for _, group := range groupBySource(batch) {
+		undelivered += b.post(ctx, group)
+	}
+	return undelivered
+}
+
+// groupBySource splits a batch by source, keeping the order of the entries
+// within each group and of the groups by their first entry
+func groupBySource(batch []LogData) [][]LogData {
+	index := make(map[string]int)
+	var groups [][]LogData
+	for _, data := range batch {
+		i, ok := index[data.Source]
+		if !ok {
+			i = len(groups)
+			index[data.Source] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], data)
+	}
+	return groups
+}
+
+// post sends one batch, counting and reporting the entries that failed,
+// and returns the number of entries neither sent nor queued for retry
+func (b *asyncBuffer) post(ctx context.Context, batch []LogData) int {
+	l := b.logger
+	rejected, queued, err := l.sendBatch(ctx, batch)
+	if err != nil {
+		for range batch {
+			l.stats.IncrementErrors()
+		}
+		if !l.options.Silent {
+			l.console.WriteString(fmt.Sprintf("[CHECKLOGS ERROR] buffered batch of %d logs: %s\n", len(batch), err.Error()))
+		}
+		if queued {
+			return 0
+		}
+		return len(batch)
+	}
+	for _, entry := range rejected {
+		l.stats.IncrementErrors()
+		if !l.options.Silent {
+			l.console.WriteString(fmt.Sprintf("[CHECKLOGS ERROR] buffered log rejected: %s\n", entry.Err.Error()))
+		}
+	}
+	return len(rejected)
+}
+
+// flush hands a flush request to the worker and waits for it to be handled
+// or for ctx to end
+func (b *asyncBuffer) flush(ctx context.Context) error {
+	req := &flushRequest{ctx: ctx, done: make(chan struct{})}
+	select {
+	case b.flushes <- req:
+	case <-b.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-req.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// stop sends what is buffered within ctx, queues the rest for retry, stops
+// the worker and returns the number of logs neither sent nor queued. If ctx
+// ends while the worker is busy with a background send, that send is
+// aborted so the worker stops without delay.
+func (b *asyncBuffer) stop(ctx context.Context) int {
+	req := &flushRequest{ctx: ctx, done: make(chan struct{})}
+	select {
+	case b.stops <- req:
+	case <-b.done:
+		return 0
+	case <-ctx.Done():
+		b.cancel()
+		select {
+		case b.stops <- req:
+		case <-b.done:
+			return 0
+		}
+	}
+	<-req.done
+	return req.undelivered
+}
+
+// Flush sends the logs buffered by BufferSize now, returning once they have
+// been sent or ctx ends. It does nothing when buffering is disabled.
+func (l *Logger) Flush(ctx context.Context) error {
+	if l.buffer == nil {
+		return nil
+	}
+	return l.buffer.flush(ctx)
+}
@@ -0,0 +1,101 @@
+package checklogs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+)
+
+// DefaultMaxAttachmentBytes is the default limit on the total size of the
+// attachments of a log entry
+const DefaultMaxAttachmentBytes = 5 * 1024 * 1024
+
+// Attachment is a named binary artifact sent along with a log entry, such as
+// a screenshot or a heap dump
+type Attachment struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type,omitempty"`
+	Data        []byte `json:"data"`
+}
+
+// attachmentSize returns the total size of the attachments in bytes
+func attachmentSize(attachments []Attachment) int {
+	total := 0
+	for _, a := range attachments {
+		total += len(a.Data)
+	}
+	return total
+}
+
+// encodeLogBody serializes a log entry for /api/logs: plain JSON, or a
+// multipart/form-data body with the entry in a "log" part followed by one
+// "attachment" part per attachment
+func encodeLogBody(data LogData) ([]byte, string, error) {
+	if len(data.Attachments) == 0 {
+		jsonData, err := json.Marshal(data)
+		return jsonData, "application/json", err
+	}
+
+	attachments := data.Attachments
+	data.Attachments = nil
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="log"`)
+	header.Set("Content-Type", "application/json")
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return nil, "", err
+	}
+	part.Write(jsonData)
+
+	for _, a := range attachments {
+		contentType := a.ContentType
+		if contentType == "" {
+			contentType = http.DetectContentType(a.Data)
+		}
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="attachment"; filename=%q`, a.Name))
+		header.Set("Content-Type", contentType)
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, "", err
+		}
+		part.Write(a.Data)
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return body.Bytes(), writer.FormDataContentType(), nil
+}
+
+// LogWithAttachment sends a log entry with the contents of r attached under
+// name. The content type is detected from the data. Reading stops with a
+// ValidationError once the attachment exceeds Options.MaxAttachmentBytes.
+func (l *Logger) LogWithAttachment(ctx context.Context, level LogLevel, message, name string, r io.Reader) error {
+	limit := l.options.MaxAttachmentBytes
+	raw, err := io.ReadAll(io.LimitReader(r, int64(limit)+1))
+	if err != nil {
+		return &CheckLogsError{Type: "ValidationError", Message: fmt.Sprintf("reading attachment %q: %s", name, err.Error())}
+	}
+	if len(raw) > limit {
+		return &CheckLogsError{Type: "ValidationError", Message: fmt.Sprintf("attachments too large (max %d bytes)", limit)}
+	}
+
+	data := l.buildLogData(ctx, level, message)
+	data.Attachments = []Attachment{{Name: name, ContentType: http.DetectContentType(raw), Data: raw}}
+	_, err = l.sendLog(ctx, data)
+	return err
+}
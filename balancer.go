@@ -0,0 +1,105 @@
+package checklogs
+
+import (
+	"sync"
+	"time"
+)
+
+// endpointEjectionPeriod is how long a failing endpoint is taken out of
+// rotation before it is tried again
+const endpointEjectionPeriod = 30 * time.Second
+
+// WeightedEndpoint is an ingestion endpoint and its share of the traffic
+type WeightedEndpoint struct {
+	URL    string `json:"url"`
+	Weight int    `json:"weight"`
+}
+
+// endpointState tracks one endpoint in the balancer
+type endpointState struct {
+	url          string
+	weight       int
+	current      int
+	ejectedUntil time.Time
+}
+
+// endpointBalancer spreads requests across endpoints with smooth weighted
+// round-robin, skipping endpoints ejected after a failure until their
+// ejection period is over
+type endpointBalancer struct {
+	mutex     sync.Mutex
+	endpoints []*endpointState
+}
+
+// newEndpointBalancer creates a balancer, or returns nil without endpoints.
+// Weights below 1 count as 1.
+func newEndpointBalancer(endpoints []WeightedEndpoint) *endpointBalancer {
+	if len(endpoints) == 0 {
+		return nil
+	}
+	b := &endpointBalancer{}
+	for _, endpoint := range endpoints {
+		weight := endpoint.Weight
+		if weight < 1 {
+			weight = 1
+		}
+		b.endpoints = append(b.endpoints, &endpointState{url: endpoint.URL, weight: weight})
+	}
+	return b
+}
+
+// next picks the endpoint for the next request. When every endpoint is
+// ejected, the one re-admitted soonest is used.
+func (b *endpointBalancer) next() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := time.Now()
+	var best *endpointState
+	total := 0
+	for _, e := range b.endpoints {
+		if now.Before(e.ejectedUntil) {
+			continue
+		}
+		e.current += e.weight
+		total += e.weight
+		if best == nil || e.current > best.current {
+			best = e
+		}
+	}
+
+	if best == nil {
+		for _, e := range b.endpoints {
+			if best == nil || e.ejectedUntil.Before(best.ejectedUntil) {
+				best = e
+			}
+		}
+		return best.url
+	}
+
+	best.current -= total
+	return best.url
+}
+
+// report records the result of a request to url, ejecting it on failure
+func (b *endpointBalancer) report(url string, healthy bool) {
+	if healthy {
+		return
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for _, e := range b.endpoints {
+		if e.url == url {
+			e.ejectedUntil = time.Now().Add(endpointEjectionPeriod)
+			e.current = 0
+		}
+	}
+}
+
+// reportEndpoint records the health of the endpoint a log was sent to
+func (l *Logger) reportEndpoint(url string, healthy bool) {
+	if l.balancer != nil {
+		l.balancer.report(url, healthy)
+	}
+}
@@ -0,0 +1,204 @@
+package checklogs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BatchEntryError is the failure of one entry of a batch
+type BatchEntryError struct {
+	Index int
+	Err   error
+}
+
+// BatchError reports the entries of a batch that were not accepted, by
+// their index in the batch
+type BatchError struct {
+	Entries []BatchEntryError
+}
+
+func (e *BatchError) Error() string {
+	parts := make([]string, len(e.Entries))
+	for i, entry := range e.Entries {
+		parts[i] = fmt.Sprintf("#%d: %s", entry.Index, entry.Err.Error())
+	}
+	return fmt.Sprintf("[BatchError] %d entries failed: %s", len(e.Entries), strings.Join(parts, "; "))
+}
+
+// errBatchAttachments rejects batch entries with attachments, which are only
+// sent as multipart requests to /api/logs
+var errBatchAttachments = &CheckLogsError{Type: "ValidationError", Message: "attachments cannot be sent in a batch; use LogWithAttachment or LogConfirmed"}
+
+// batchRequest is the body posted to /api/logs/batch
+type batchRequest struct {
+	Logs []LogData `json:"logs"`
+}
+
+// batchResponse is the body of a 207 partial success, listing the entries
+// the server rejected by their index in the request
+type batchResponse struct {
+	Rejected []struct {
+		Index int    `json:"index"`
+		Error string `json:"error"`
+	} `json:"rejected"`
+}
+
+// LogBatch sends several logs in a single request to /api/logs/batch. Each
+// entry gets the logger's defaults and context like a log sent with Info or
+// Error, and is validated; invalid entries, and entries with attachments,
+// which the batch endpoint does not accept, are left out and the rest are
+// sent. Entries that failed validation or were rejected by the server in a
+// 207 partial success are reported in a *BatchError. If the request itself
+// fails, its error is returned instead and, when the failure is transient,
+// the sent entries are queued for retry.
+func (l *Logger) LogBatch(ctx context.Context, entries []LogData) error {
+	if l.closed.Load() {
+		return ErrClientClosed
+	}
+
+	var failed []BatchEntryError
+	batch := make([]LogData, 0, len(entries))
+	indices := make([]int, 0, len(entries))
+	for i, data := range entries {
+		if len(data.Attachments) > 0 {
+			failed = append(failed, BatchEntryError{Index: i, Err: errBatchAttachments})
+			continue
+		}
+		data = l.withDefaults(ctx, data)
+		l.prepareLogData(&data)
+		if err := l.validateLogData(&data); err != nil {
+			failed = append(failed, BatchEntryError{Index: i, Err: err})
+			continue
+		}
+		l.stats.IncrementLogs(data.Level)
+
+		if l.options.ConsoleOutput && !l.options.Silent {
+			l.console.WriteString(l.formatConsole(data))
+		}
+		for _, sink := range l.options.Sinks {
+			if err := sink.Write(ctx, data); err != nil && !l.options.Silent {
+				l.console.WriteString(fmt.Sprintf("[CHECKLOGS ERROR] sink: %s\n", err.Error()))
+			}
+		}
+
+		batch = append(batch, data)
+		indices = append(indices, i)
+	}
+
+	if len(batch) > 0 {
+		rejected, _, err := l.sendBatch(ctx, batch)
+		if err != nil {
+			for range batch {
+				l.stats.IncrementErrors()
+			}
+			return err
+		}
+		for _, entry := range rejected {
+			l.stats.IncrementErrors()
+			failed = append(failed, BatchEntryError{Index: indices[entry.Index], Err: entry.Err})
+		}
+	}
+
+	if len(failed) > 0 {
+		sort.Slice(failed, func(i, j int) bool { return failed[i].Index < failed[j].Index })
+		return &BatchError{Entries: failed}
+	}
+	return nil
+}
+
+// sendBatch posts validated entries to /api/logs/batch and returns the
+// entries rejected in a partial success, by their index in batch. On a
+// transient failure every entry is queued for retry, and queued is true.
+func (l *Logger) sendBatch(ctx context.Context, batch []LogData) (rejected []BatchEntryError, queued bool, err error) {
+	// Skip HTTP request if no API key
+	if l.apiKey == "" {
+		if l.options.ConsoleOnly {
+			return nil, false, nil
+		}
+		return nil, false, &CheckLogsError{Type: "ConfigurationError", Message: "API key is required"}
+	}
+
+	// Skip HTTP request in silent mode
+	if l.options.Silent {
+		return nil, false, nil
+	}
+
+	jsonData, err := json.Marshal(batchRequest{Logs: batch})
+	if err != nil {
+		return nil, false, &CheckLogsError{Type: "SerializationError", Message: err.Error()}
+	}
+	body, contentEncoding := l.compressBody(jsonData)
+
+	queueAll := func() {
+		for _, data := range batch {
+			l.addToRetryQueue(data)
+		}
+	}
+
+	baseURL, err := l.baseURL(ctx)
+	if err != nil {
+		queueAll()
+		return nil, true, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/api/logs/batch", bytes.NewReader(body))
+	if err != nil {
+		queueAll()
+		return nil, true, &CheckLogsError{Type: "NetworkError", Message: err.Error(), Cause: err}
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if contentEncoding != "" {
+		req.Header.Set("Content-Encoding", contentEncoding)
+	}
+	req.Header.Set("Authorization", "Bearer "+l.apiKey)
+	req.Header.Set("User-Agent", "CheckLogs-Go-SDK/"+Version)
+
+	start := time.Now()
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		l.reportEndpoint(baseURL, false)
+		queueAll()
+		return nil, true, &CheckLogsError{Type: "NetworkError", Message: err.Error(), Cause: err}
+	}
+	defer resp.Body.Close()
+	l.limitResponse(resp)
+	l.latency.record(time.Since(start))
+	l.reportEndpoint(baseURL, resp.StatusCode != 429 && resp.StatusCode < 500)
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		err, shouldRetry := statusError(resp.StatusCode, body)
+		if shouldRetry {
+			queueAll()
+		}
+		return nil, shouldRetry, err
+	}
+	l.stats.AddBytes(len(body))
+
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, false, nil
+	}
+
+	var result batchResponse
+	if err := l.decodeResponse(resp, &result); err != nil {
+		return nil, false, err
+	}
+	for _, entry := range result.Rejected {
+		if entry.Index < 0 || entry.Index >= len(batch) {
+			continue
+		}
+		rejected = append(rejected, BatchEntryError{
+			Index: entry.Index,
+			Err:   &CheckLogsError{Type: "APIError", Message: entry.Error, Code: resp.StatusCode},
+		})
+	}
+	return rejected, false, nil
+}
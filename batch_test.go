@@ -0,0 +1,357 @@
+package checklogs
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogBatchPreparesEntriesLikeSingleLogs(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(*Options)
+		context   map[string]interface{}
+		message   string
+	}{
+		{
+			name: "enrichers",
+			configure: func(o *Options) {
+				o.Enrichers = []Enricher{EnricherFunc(func(data *LogData) {
+					data.Context["region"] = "eu-west-1"
+				})}
+			},
+			context: map[string]interface{}{"user": "ada"},
+			message: "enriched",
+		},
+		{
+			name: "type formatters",
+			configure: func(o *Options) {
+				o.TypeFormatters = map[reflect.Type]func(interface{}) interface{}{
+					reflect.TypeOf(time.Duration(0)): func(v interface{}) interface{} {
+						return v.(time.Duration).String()
+					},
+				}
+			},
+			context: map[string]interface{}{"elapsed": 1500 * time.Millisecond},
+			message: "formatted",
+		},
+		{
+			name: "truncate oversized",
+			configure: func(o *Options) {
+				o.TruncateOversized = true
+			},
+			context: map[string]interface{}{"user": "ada"},
+			message: strings.Repeat("x", DefaultMaxMessageLength+10),
+		},
+		{
+			name: "value caps",
+			configure: func(o *Options) {
+				o.MaxValueBytes = 16
+			},
+			context: map[string]interface{}{"body": strings.Repeat("y", 64)},
+			message: "capped",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			server := newTestServer(t)
+			logger := newTestLogger(t, server, tt.configure)
+
+			if err := logger.Info(ctx, tt.message, tt.context); err != nil {
+				t.Fatalf("Info: %v", err)
+			}
+			if err := logger.LogBatch(ctx, []LogData{{Message: tt.message, Level: Info, Context: tt.context}}); err != nil {
+				t.Fatalf("LogBatch: %v", err)
+			}
+
+			logs := server.logs()
+			if len(logs) != 2 {
+				t.Fatalf("server received %d logs, want 2", len(logs))
+			}
+			single, batched := logs[0], logs[1]
+			if single.Message != batched.Message {
+				t.Errorf("batched message = %q, want %q", batched.Message, single.Message)
+			}
+			if !reflect.DeepEqual(single.Context, batched.Context) {
+				t.Errorf("batched context = %v, want %v", batched.Context, single.Context)
+			}
+		})
+	}
+}
+
+func TestBufferedModeSendsAttachmentsAsMultipart(t *testing.T) {
+	tests := []struct {
+		name            string
+		attachment      bool
+		wantPath        string
+		wantContentType string
+	}{
+		{"plain log is batched", false, "/api/logs/batch", "application/json"},
+		{"log with attachment bypasses the buffer", true, "/api/logs", "multipart/form-data"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			server := newTestServer(t)
+			logger := newTestLogger(t, server, func(o *Options) {
+				o.BufferSize = 10
+			})
+
+			var err error
+			if tt.attachment {
+				err = logger.LogWithAttachment(ctx, Error, "crash", "dump.txt", strings.NewReader("heap dump"))
+			} else {
+				err = logger.Info(ctx, "buffered")
+			}
+			if err != nil {
+				t.Fatalf("log: %v", err)
+			}
+			if err := logger.Close(ctx); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+
+			requests := server.received()
+			if len(requests) != 1 {
+				t.Fatalf("server received %d requests, want 1", len(requests))
+			}
+			if requests[0].Path != tt.wantPath {
+				t.Errorf("path = %s, want %s", requests[0].Path, tt.wantPath)
+			}
+			if !strings.HasPrefix(requests[0].ContentType, tt.wantContentType) {
+				t.Errorf("content type = %s, want %s", requests[0].ContentType, tt.wantContentType)
+			}
+		})
+	}
+}
+
+func TestBatchBySource(t *testing.T) {
+	tests := []struct {
+		name        string
+		bySource    bool
+		sources     []string
+		wantBatches [][]string
+	}{
+		{"mixed batch by default", false, []string{"a", "b", "a"}, [][]string{{"a", "b", "a"}}},
+		{"one batch per source", true, []string{"a", "b", "a", "c"}, [][]string{{"a", "a"}, {"b"}, {"c"}}},
+		{"single source", true, []string{"a", "a"}, [][]string{{"a", "a"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			server := newTestServer(t)
+			logger := newTestLogger(t, server, func(o *Options) {
+				o.BufferSize = 10
+				o.FlushInterval = time.Hour
+				o.BatchBySource = tt.bySource
+			})
+
+			for _, source := range tt.sources {
+				logger.Infow(ctx, "tenant log", WithSource(source))
+			}
+			if err := logger.Flush(ctx); err != nil {
+				t.Fatalf("Flush: %v", err)
+			}
+
+			var batches [][]string
+			for _, req := range server.received() {
+				var sources []string
+				for _, data := range req.Logs {
+					sources = append(sources, data.Source)
+				}
+				batches = append(batches, sources)
+			}
+			if !reflect.DeepEqual(batches, tt.wantBatches) {
+				t.Errorf("batches = %v, want %v", batches, tt.wantBatches)
+			}
+		})
+	}
+}
+
+func TestBatchTransformer(t *testing.T) {
+	dedupe := func(batch []LogData) []LogData {
+		seen := make(map[string]bool)
+		var kept []LogData
+		for _, data := range batch {
+			if !seen[data.Message] {
+				seen[data.Message] = true
+				kept = append(kept, data)
+			}
+		}
+		return kept
+	}
+
+	tests := []struct {
+		name        string
+		transform   func([]LogData) []LogData
+		bySource    bool
+		messages    []string
+		wantBatches [][]string
+	}{
+		{"dedupe", dedupe, false, []string{"a", "b", "a", "a"}, [][]string{{"a", "b"}}},
+		{"drop all", func([]LogData) []LogData { return nil }, false, []string{"a", "b"}, nil},
+		{"reverse", func(batch []LogData) []LogData {
+			reversed := make([]LogData, 0, len(batch))
+			for i := len(batch) - 1; i >= 0; i-- {
+				reversed = append(reversed, batch[i])
+			}
+			return reversed
+		}, false, []string{"a", "b", "c"}, [][]string{{"c", "b", "a"}}},
+		{"before grouping by source", dedupe, true, []string{"a", "b", "a"}, [][]string{{"a"}, {"b"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			server := newTestServer(t)
+			logger := newTestLogger(t, server, func(o *Options) {
+				o.BufferSize = 10
+				o.FlushInterval = time.Hour
+				o.BatchTransformer = tt.transform
+				o.BatchBySource = tt.bySource
+			})
+
+			for _, message := range tt.messages {
+				logger.Infow(ctx, message, WithSource("source-"+message))
+			}
+			if err := logger.Flush(ctx); err != nil {
+				t.Fatalf("Flush: %v", err)
+			}
+
+			var batches [][]string
+			for _, req := range server.received() {
+				var messages []string
+				for _, data := range req.Logs {
+					messages = append(messages, data.Message)
+				}
+				batches = append(batches, messages)
+			}
+			if !reflect.DeepEqual(batches, tt.wantBatches) {
+				t.Errorf("batches = %v, want %v", batches, tt.wantBatches)
+			}
+		})
+	}
+}
+
+func TestBufferStartupJitterDelaysFirstFlush(t *testing.T) {
+	tests := []struct {
+		name          string
+		jitter        time.Duration
+		wantDelivered int
+	}{
+		{"no jitter", 0, 1},
+		{"jitter", time.Hour, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			logger := newTestLogger(t, server, func(o *Options) {
+				o.BufferSize = 10
+				o.FlushInterval = 20 * time.Millisecond
+				o.StartupJitter = tt.jitter
+			})
+
+			logger.Info(context.Background(), "buffered")
+			time.Sleep(200 * time.Millisecond)
+
+			if got := len(server.logs()); got != tt.wantDelivered {
+				t.Errorf("server received %d logs before the first flush, want %d", got, tt.wantDelivered)
+			}
+		})
+	}
+}
+
+func TestLogBatchAppliesLoggerDefaults(t *testing.T) {
+	for _, tt := range defaultsCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), ctxKey("tenant"), "acme")
+			server := newTestServer(t)
+			logger := newTestLogger(t, server, tt.configure)
+			if tt.child != nil {
+				logger = logger.Child(tt.child)
+			}
+
+			call := map[string]interface{}{"action": "export"}
+			if err := logger.Info(ctx, "batched", call); err != nil {
+				t.Fatalf("Info: %v", err)
+			}
+			if err := logger.LogBatch(ctx, []LogData{{Message: "batched", Level: Info, Context: call}}); err != nil {
+				t.Fatalf("LogBatch: %v", err)
+			}
+
+			logs := server.logs()
+			if len(logs) != 2 {
+				t.Fatalf("server received %d logs, want 2", len(logs))
+			}
+			checkDefaults(t, logs[0], logs[1], logger.options.Sequence)
+		})
+	}
+}
+
+func TestLogBatchRejectsAttachments(t *testing.T) {
+	server := newTestServer(t)
+	logger := newTestLogger(t, server, nil)
+
+	err := logger.LogBatch(context.Background(), []LogData{
+		{Message: "plain", Level: Info},
+		{Message: "with attachment", Level: Info, Attachments: []Attachment{{Name: "dump.txt", Data: []byte("data")}}},
+	})
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Entries) != 1 || batchErr.Entries[0].Index != 1 {
+		t.Fatalf("LogBatch = %v, want a BatchError for entry 1", err)
+	}
+	if !errors.Is(batchErr.Entries[0].Err, ErrValidation) {
+		t.Errorf("entry error = %v, want a ValidationError", batchErr.Entries[0].Err)
+	}
+	logs := server.logs()
+	if len(logs) != 1 || logs[0].Message != "plain" {
+		t.Errorf("server received %v, want only the plain entry", logs)
+	}
+}
+
+func TestBufferedModeRejectsInvalidLogsSynchronously(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   LogLevel
+		message string
+		context map[string]interface{}
+	}{
+		{"empty message", Info, "", nil},
+		{"message too long", Info, strings.Repeat("m", DefaultMaxMessageLength+1), nil},
+		{"oversized context", Info, "event", map[string]interface{}{"v": strings.Repeat("x", DefaultMaxContextBytes)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			server := newTestServer(t)
+			logger := newTestLogger(t, server, func(o *Options) {
+				o.BufferSize = 10
+				o.FlushInterval = time.Hour
+			})
+
+			outcome, err := logger.LogResult(ctx, tt.level, tt.message, tt.context)
+			if !errors.Is(err, ErrValidation) {
+				t.Fatalf("LogResult error = %v, want a ValidationError", err)
+			}
+			if outcome != ValidationFailed {
+				t.Errorf("outcome = %v, want ValidationFailed", outcome)
+			}
+
+			if err := logger.Close(ctx); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+			if got := len(server.received()); got != 0 {
+				t.Errorf("server received %d requests, want 0", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package checklogs
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func BenchmarkBuildLogData(b *testing.B) {
+	defaults := make(map[string]interface{}, 20)
+	for i := 0; i < 20; i++ {
+		defaults[fmt.Sprintf("key_%02d", i)] = fmt.Sprintf("value %d", i)
+	}
+	logger := NewLogger("", &Options{Context: defaults})
+	call := map[string]interface{}{"user_id": 42, "action": "login"}
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.buildLogData(ctx, Info, "benchmark", call)
+	}
+}
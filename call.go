@@ -0,0 +1,86 @@
+package checklogs
+
+import (
+	"context"
+	"time"
+)
+
+// callOption changes how a single log call is handled
+type callOption func(*callOptions)
+
+// callOptions holds the per-call overrides of the logger's options
+type callOptions struct {
+	noConsole bool
+	replay    bool
+	closing   bool
+	source    string
+	timestamp time.Time
+}
+
+// NoConsole skips console output for this call even when ConsoleOutput is
+// enabled; the log is still sent to the API and to sinks. Call options are
+// fields, passed to the w level methods:
+//
+//	logger.Infow(ctx, "password reset", checklogs.String("user", id), checklogs.NoConsole())
+func NoConsole() Field {
+	return Field{option: func(o *callOptions) {
+		o.noConsole = true
+	}}
+}
+
+// WithSource overrides the logger's source for this call
+func WithSource(source string) Field {
+	return Field{option: func(o *callOptions) {
+		o.source = source
+	}}
+}
+
+// WithTimestamp sets the time of the event for this call, instead of now,
+// for example when replaying or backfilling historical events
+func WithTimestamp(t time.Time) Field {
+	return Field{option: func(o *callOptions) {
+		o.timestamp = t
+	}}
+}
+
+// replay marks a log replayed from the retry queue. It was already echoed to
+// the console and counted in the stats when first logged.
+func replay() callOption {
+	return func(o *callOptions) {
+		o.noConsole = true
+		o.replay = true
+	}
+}
+
+// closing marks the summary logged by Close, which is sent after the logger
+// stops accepting logs
+func closing() callOption {
+	return func(o *callOptions) {
+		o.closing = true
+	}
+}
+
+// newCallOptions applies call options over the defaults
+func newCallOptions(opts []callOption) callOptions {
+	var call callOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&call)
+		}
+	}
+	return call
+}
+
+// buildCallLogData builds a log entry, applying the call options that change
+// the entry itself
+func (l *Logger) buildCallLogData(ctx context.Context, level LogLevel, message string, contexts []map[string]interface{}, opts []callOption) LogData {
+	data := l.buildLogData(ctx, level, message, contexts...)
+	call := newCallOptions(opts)
+	if call.source != "" {
+		data.Source = call.source
+	}
+	if !call.timestamp.IsZero() {
+		data.Timestamp = call.timestamp
+	}
+	return data
+}
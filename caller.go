@@ -0,0 +1,75 @@
+package checklogs
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// DefaultMaxStackTraceBytes is the default size limit of the stack trace
+// captured by CaptureStackTrace, leaving room in the context for other fields
+const DefaultMaxStackTraceBytes = 2048
+
+// maxCallerDepth bounds the stack walked to find the caller of a log
+const maxCallerDepth = 32
+
+// callerSkipPrefixes are the packages whose frames are skipped when looking
+// for the caller: the SDK itself and the loggers it adapts
+var callerSkipPrefixes = []string{
+	reflect.TypeOf(Logger{}).PkgPath() + ".",
+	"log.",
+	"log/slog.",
+	"go.uber.org/zap",
+}
+
+// callerInfo returns the "file.go:123" location and the "pkg.Func" name of
+// the first stack frame outside the SDK, whichever method, adapter or child
+// logger the log went through
+func callerInfo() (string, string, bool) {
+	pcs := make([]uintptr, maxCallerDepth)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !isSDKFrame(frame.Function) {
+			function := frame.Function
+			if i := strings.LastIndex(function, "/"); i >= 0 {
+				function = function[i+1:]
+			}
+			return fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line), function, true
+		}
+		if !more {
+			return "", "", false
+		}
+	}
+}
+
+// isSDKFrame reports whether a function belongs to a skipped package
+func isSDKFrame(function string) bool {
+	for _, prefix := range callerSkipPrefixes {
+		if strings.HasPrefix(function, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// stackTrace returns the stack of the current goroutine from the user's
+// call site down, dropping the header and the SDK's own frames, truncated
+// to maxBytes
+func stackTrace(maxBytes int) string {
+	buf := make([]byte, 16*1024)
+	buf = buf[:runtime.Stack(buf, false)]
+
+	// Frames are a function line followed by a tab-indented location line
+	lines := strings.Split(strings.TrimSpace(string(buf)), "\n")
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "goroutine ") {
+		lines = lines[1:]
+	}
+	for len(lines) >= 2 && isSDKFrame(lines[0]) {
+		lines = lines[2:]
+	}
+	return truncateString(strings.Join(lines, "\n"), maxBytes)
+}
@@ -0,0 +1,115 @@
+package checklogs
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestChildSharesRetryQueueWithParent(t *testing.T) {
+	tests := []struct {
+		name  string
+		log   func(ctx context.Context, parent *Logger) error
+		flush func(ctx context.Context, parent, child *Logger) int
+	}{
+		{"child logs, parent flushes", func(ctx context.Context, parent *Logger) error {
+			return parent.Child(map[string]interface{}{"component": "db"}).Error(ctx, "query failed")
+		}, func(ctx context.Context, parent, child *Logger) int {
+			return parent.FlushRetryQueue(ctx)
+		}},
+		{"parent logs, child flushes", func(ctx context.Context, parent *Logger) error {
+			return parent.Error(ctx, "query failed")
+		}, func(ctx context.Context, parent, child *Logger) int {
+			return child.FlushRetryQueue(ctx)
+		}},
+		{"grandchild logs, parent flushes", func(ctx context.Context, parent *Logger) error {
+			return parent.Child(nil).Child(nil).Error(ctx, "query failed")
+		}, func(ctx context.Context, parent, child *Logger) int {
+			return parent.FlushRetryQueue(ctx)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			server := newTestServer(t)
+			parent := newTestLogger(t, server, nil)
+			child := parent.Child(nil)
+
+			server.setStatus(http.StatusServiceUnavailable)
+			if err := tt.log(ctx, parent); err == nil {
+				t.Fatal("log during outage succeeded")
+			}
+			if got := parent.GetRetryQueueSize(); got != 1 {
+				t.Fatalf("parent retry queue size = %d, want 1", got)
+			}
+			if got := child.GetRetryQueueSize(); got != 1 {
+				t.Fatalf("child retry queue size = %d, want 1", got)
+			}
+
+			server.setStatus(http.StatusOK)
+			if got := tt.flush(ctx, parent, child); got != 1 {
+				t.Errorf("flushed = %d, want 1", got)
+			}
+			if got := len(server.logs()); got != 1 {
+				t.Errorf("server received %d logs, want 1", got)
+			}
+			if got := parent.GetRetryQueueSize(); got != 0 {
+				t.Errorf("retry queue size after flush = %d, want 0", got)
+			}
+		})
+	}
+}
+
+func TestChildSharesStatsWithParent(t *testing.T) {
+	ctx := context.Background()
+	server := newTestServer(t)
+	parent := newTestLogger(t, server, nil)
+	child := parent.Child(map[string]interface{}{"component": "api"})
+
+	parent.Info(ctx, "from parent")
+	child.Info(ctx, "from child")
+	child.Child(nil).Warning(ctx, "from grandchild")
+
+	for _, logger := range []*Logger{parent, child} {
+		stats := logger.GetStats()
+		if stats.TotalLogs != 3 {
+			t.Errorf("TotalLogs = %d, want 3", stats.TotalLogs)
+		}
+		if stats.ByLevel[Info] != 2 || stats.ByLevel[Warning] != 1 {
+			t.Errorf("ByLevel = %v, want 2 info and 1 warning", stats.ByLevel)
+		}
+	}
+
+	logs := server.logs()
+	if len(logs) != 3 {
+		t.Fatalf("server received %d logs, want 3", len(logs))
+	}
+	if logs[1].Context["component"] != "api" {
+		t.Errorf("child log context = %v, want component=api", logs[1].Context)
+	}
+}
+
+func TestChildLogsQueuedDuringOutageFlushOnParentClose(t *testing.T) {
+	ctx := context.Background()
+	server := newTestServer(t)
+	parent := newTestLogger(t, server, func(o *Options) {
+		o.AutoRetry = true
+	})
+	child := parent.Child(nil)
+
+	server.setStatus(http.StatusServiceUnavailable)
+	child.Error(ctx, "first")
+	child.Error(ctx, "second")
+
+	server.setStatus(http.StatusOK)
+	if err := parent.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := len(server.logs()); got != 2 {
+		t.Errorf("server received %d logs, want 2", got)
+	}
+	if got := child.GetRetryQueueSize(); got != 0 {
+		t.Errorf("retry queue size after close = %d, want 0", got)
+	}
+}
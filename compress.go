@@ -0,0 +1,29 @@
+package checklogs
+
+import (
+	"bytes"
+	"compress/gzip"
+)
+
+// compressThreshold is the body size from which Compress gzips requests;
+// smaller bodies gain too little to be worth it
+const compressThreshold = 1024
+
+// compressBody gzips a request body when Compress is set and the body is
+// large enough, returning the body to send and its Content-Encoding. A
+// failure to compress sends the body uncompressed.
+func (l *Logger) compressBody(body []byte) ([]byte, string) {
+	if !l.options.Compress || len(body) < compressThreshold {
+		return body, ""
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(body); err != nil {
+		return body, ""
+	}
+	if err := gz.Close(); err != nil {
+		return body, ""
+	}
+	return buf.Bytes(), "gzip"
+}
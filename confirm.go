@@ -0,0 +1,127 @@
+package checklogs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// maxConfirmAttempts bounds the attempts made by LogConfirmed
+const maxConfirmAttempts = 5
+
+// Receipt is the server's confirmation that a log was stored
+type Receipt struct {
+	ID         string    `json:"id"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// LogConfirmed sends a log synchronously and waits for the server to confirm
+// it was stored. Transient failures are retried, up to 5 attempts while ctx
+// allows; the log is never put in the retry queue, so the caller always gets
+// a conclusive outcome: a receipt, or an error meaning the log was not
+// stored. Use it for audit-critical logs. The entry gets the logger's
+// defaults and context, and is processed like a log sent with Info or Error.
+func (l *Logger) LogConfirmed(ctx context.Context, data LogData) (*Receipt, error) {
+	if l.closed.Load() {
+		return nil, ErrClientClosed
+	}
+	if l.apiKey == "" {
+		return nil, &CheckLogsError{Type: "ConfigurationError", Message: "API key is required"}
+	}
+
+	data = l.withDefaults(ctx, data)
+	l.prepareLogData(&data)
+	if err := l.validateLogData(&data); err != nil {
+		return nil, err
+	}
+	l.stats.IncrementLogs(data.Level)
+
+	if l.options.ConsoleOutput && !l.options.Silent {
+		l.console.WriteString(l.formatConsole(data))
+	}
+	for _, sink := range l.options.Sinks {
+		if err := sink.Write(ctx, data); err != nil && !l.options.Silent {
+			l.console.WriteString(fmt.Sprintf("[CHECKLOGS ERROR] sink: %s\n", err.Error()))
+		}
+	}
+
+	body, contentType, err := encodeLogBody(data)
+	if err != nil {
+		return nil, &CheckLogsError{Type: "SerializationError", Message: err.Error()}
+	}
+	body, contentEncoding := l.compressBody(body)
+
+	var receipt *Receipt
+	for attempt := 0; attempt < maxConfirmAttempts; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(time.Duration(1<<(attempt-1)) * 250 * time.Millisecond)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				l.stats.IncrementErrors()
+				return nil, &CheckLogsError{Type: "NetworkError", Message: "log not confirmed: " + ctx.Err().Error(), Cause: ctx.Err()}
+			case <-timer.C:
+			}
+		}
+
+		receipt, err = l.postConfirmed(ctx, body, contentType, contentEncoding)
+		if err == nil || !isTransientError(err) {
+			break
+		}
+	}
+	if err != nil {
+		l.stats.IncrementErrors()
+		return nil, err
+	}
+	l.stats.AddBytes(len(body))
+	return receipt, nil
+}
+
+// postConfirmed posts one serialized log and decodes the receipt
+func (l *Logger) postConfirmed(ctx context.Context, body []byte, contentType, contentEncoding string) (*Receipt, error) {
+	baseURL, err := l.baseURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/api/logs", bytes.NewReader(body))
+	if err != nil {
+		return nil, &CheckLogsError{Type: "NetworkError", Message: err.Error(), Cause: err}
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	if contentEncoding != "" {
+		req.Header.Set("Content-Encoding", contentEncoding)
+	}
+	req.Header.Set("Authorization", "Bearer "+l.apiKey)
+	req.Header.Set("User-Agent", "CheckLogs-Go-SDK/"+Version)
+
+	start := time.Now()
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		l.reportEndpoint(baseURL, false)
+		return nil, &CheckLogsError{Type: "NetworkError", Message: err.Error(), Cause: err}
+	}
+	defer resp.Body.Close()
+	l.limitResponse(resp)
+	l.latency.record(time.Since(start))
+	l.reportEndpoint(baseURL, resp.StatusCode != 429 && resp.StatusCode < 500)
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		err, _ := statusError(resp.StatusCode, body)
+		return nil, err
+	}
+
+	var receipt Receipt
+	if err := l.decodeResponse(resp, &receipt); err != nil {
+		return nil, err
+	}
+	if receipt.ID == "" {
+		return nil, &CheckLogsError{Type: "APIError", Message: "server did not return a log ID", Code: resp.StatusCode}
+	}
+	return &receipt, nil
+}
@@ -0,0 +1,112 @@
+package checklogs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestLogConfirmedStatusErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		want   error
+	}{
+		{"unauthorized", http.StatusUnauthorized, ErrUnauthorized},
+		{"rate limited", http.StatusTooManyRequests, ErrRateLimited},
+		{"bad request", http.StatusBadRequest, ErrValidation},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server := newTestServer(t)
+			server.setStatus(tt.status)
+			logger := newTestLogger(t, server, nil)
+
+			_, err := logger.LogConfirmed(context.Background(), LogData{Message: "audit", Level: Info})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("LogConfirmed error = %v, want %v", err, tt.want)
+			}
+			var e *CheckLogsError
+			if !errors.As(err, &e) || e.Code != tt.status {
+				t.Errorf("error code = %v, want %d", err, tt.status)
+			}
+		})
+	}
+}
+
+// ctxKey is the type of the context keys used by the tests
+type ctxKey string
+
+// defaultsCases configures loggers whose defaults a caller-built entry must
+// get like a logged message
+var defaultsCases = []struct {
+	name      string
+	configure func(*Options)
+	child     map[string]interface{}
+}{
+	{"default context", func(o *Options) { o.Context = map[string]interface{}{"service": "billing"} }, nil},
+	{"child context", nil, map[string]interface{}{"request_id": "req-42"}},
+	{"context extractors", func(o *Options) {
+		o.ContextExtractors = map[string]func(ctx context.Context) (interface{}, bool){
+			"tenant": func(ctx context.Context) (interface{}, bool) {
+				v, ok := ctx.Value(ctxKey("tenant")).(string)
+				return v, ok
+			},
+		}
+	}, nil},
+	{"trace context", func(o *Options) {
+		o.TraceContext = func(ctx context.Context) (string, string) { return "trace-1", "span-1" }
+	}, nil},
+	{"SDK meta", func(o *Options) { o.IncludeSDKMeta = true }, nil},
+	{"sequence", func(o *Options) { o.Sequence = true }, nil},
+}
+
+// checkDefaults compares an entry sent by a caller with the same log sent
+// by Info just before it
+func checkDefaults(t *testing.T, single, sent LogData, sequence bool) {
+	t.Helper()
+	if !reflect.DeepEqual(sent.Context, single.Context) {
+		t.Errorf("context = %v, want %v", sent.Context, single.Context)
+	}
+	if sent.TraceID != single.TraceID || sent.SpanID != single.SpanID {
+		t.Errorf("trace = %q/%q, want %q/%q", sent.TraceID, sent.SpanID, single.TraceID, single.SpanID)
+	}
+	if sent.Hostname != single.Hostname {
+		t.Errorf("hostname = %q, want %q", sent.Hostname, single.Hostname)
+	}
+	if sequence && sent.Seq != single.Seq+1 {
+		t.Errorf("seq = %d, want %d", sent.Seq, single.Seq+1)
+	}
+}
+
+func TestLogConfirmedAppliesLoggerDefaults(t *testing.T) {
+	for _, tt := range defaultsCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), ctxKey("tenant"), "acme")
+			server := newTestServer(t)
+			logger := newTestLogger(t, server, tt.configure)
+			if tt.child != nil {
+				logger = logger.Child(tt.child)
+			}
+
+			call := map[string]interface{}{"action": "export"}
+			if err := logger.Info(ctx, "audit", call); err != nil {
+				t.Fatalf("Info: %v", err)
+			}
+			if _, err := logger.LogConfirmed(ctx, LogData{Message: "audit", Level: Info, Context: call}); err != nil {
+				t.Fatalf("LogConfirmed: %v", err)
+			}
+
+			logs := server.logs()
+			if len(logs) != 2 {
+				t.Fatalf("server received %d logs, want 2", len(logs))
+			}
+			checkDefaults(t, logs[0], logs[1], logger.options.Sequence)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package checklogs
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// consoleFlushInterval is how long buffered console output may sit before
+// it is written out
+const consoleFlushInterval = 100 * time.Millisecond
+
+// consoleWriter serializes console output so lines from concurrent
+// goroutines never interleave, optionally buffering writes
+type consoleWriter struct {
+	mutex     sync.Mutex
+	out       io.Writer
+	buf       *bufio.Writer
+	scheduled bool
+	color     bool
+}
+
+// newConsoleWriter creates a console writer, buffered when requested. Color
+// is only enabled when out is a terminal.
+func newConsoleWriter(out io.Writer, buffered, color bool) *consoleWriter {
+	c := &consoleWriter{out: out, color: color && isTerminal(out)}
+	if buffered {
+		c.buf = bufio.NewWriter(out)
+	}
+	return c
+}
+
+// WriteString writes a complete line to the console
+func (c *consoleWriter) WriteString(line string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.buf == nil {
+		io.WriteString(c.out, line)
+		return
+	}
+
+	c.buf.WriteString(line)
+	if !c.scheduled {
+		c.scheduled = true
+		time.AfterFunc(consoleFlushInterval, func() { c.Flush() })
+	}
+}
+
+// Flush writes any buffered console output
+func (c *consoleWriter) Flush() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.scheduled = false
+	if c.buf == nil {
+		return nil
+	}
+	return c.buf.Flush()
+}
+
+// isTerminal reports whether w is a terminal rather than a file or a pipe
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// levelColors are the ANSI colors of the level in colored text lines
+var levelColors = map[LogLevel]string{
+	Debug:    "\x1b[90m",   // gray
+	Info:     "\x1b[36m",   // cyan
+	Warning:  "\x1b[33m",   // yellow
+	Error:    "\x1b[31m",   // red
+	Critical: "\x1b[1;31m", // bold red
+}
+
+// Console output formats
+const (
+	ConsoleFormatText = "text"
+	ConsoleFormatJSON = "json"
+)
+
+// DefaultConsoleTimeFormat is the layout of the time in text console lines
+const DefaultConsoleTimeFormat = "15:04:05"
+
+// consoleFormat returns the console format for a level: its entry in
+// ConsoleFormatByLevel, or ConsoleFormat
+func (l *Logger) consoleFormat(level LogLevel) string {
+	if format, ok := l.options.ConsoleFormatByLevel[level]; ok {
+		return format
+	}
+	if l.options.ConsoleFormat != "" {
+		return l.options.ConsoleFormat
+	}
+	return ConsoleFormatText
+}
+
+// formatConsole renders a log entry for the console: a short text line, or
+// the full entry as a single JSON line
+func (l *Logger) formatConsole(data LogData) string {
+	if l.consoleFormat(data.Level) == ConsoleFormatJSON {
+		if line, err := json.Marshal(data); err == nil {
+			return string(line) + "\n"
+		}
+	}
+
+	timeFormat := l.options.ConsoleTimeFormat
+	if timeFormat == "" {
+		timeFormat = DefaultConsoleTimeFormat
+	}
+	level := string(data.Level)
+	if color, ok := levelColors[data.Level]; ok && l.console.color {
+		level = color + level + "\x1b[0m"
+	}
+	line := fmt.Sprintf("[%s] %s: %s\n", data.Timestamp.Format(timeFormat), level, data.Message)
+	if l.options.ConsoleDetail && data.Detail != "" {
+		line += strings.TrimRight(data.Detail, "\n") + "\n"
+	}
+	return line
+}
@@ -0,0 +1,100 @@
+package checklogs
+
+import "os"
+
+// Enricher adds environment-specific metadata to a log entry. Enrichers run
+// before each log is sent, after the logger's defaults have been applied,
+// in the order they are listed in Options.Enrichers.
+type Enricher interface {
+	Enrich(data *LogData)
+}
+
+// EnricherFunc adapts an ordinary function to the Enricher interface
+type EnricherFunc func(data *LogData)
+
+// Enrich calls f(data)
+func (f EnricherFunc) Enrich(data *LogData) {
+	f(data)
+}
+
+// staticEnricher adds a fixed set of context fields, never overriding keys
+// already present on the entry
+type staticEnricher struct {
+	fields map[string]interface{}
+}
+
+func (e *staticEnricher) Enrich(data *LogData) {
+	if len(e.fields) == 0 {
+		return
+	}
+	if data.Context == nil {
+		data.Context = make(map[string]interface{}, len(e.fields))
+	}
+	for k, v := range e.fields {
+		if _, exists := data.Context[k]; !exists {
+			data.Context[k] = v
+		}
+	}
+}
+
+// fieldsFromEnv maps environment variables to context keys, skipping unset ones
+func fieldsFromEnv(mapping map[string]string) map[string]interface{} {
+	fields := make(map[string]interface{})
+	for env, key := range mapping {
+		if value := os.Getenv(env); value != "" {
+			fields[key] = value
+		}
+	}
+	return fields
+}
+
+// KubernetesEnricher adds pod identity exposed through the downward API as
+// environment variables (POD_NAME, POD_NAMESPACE, POD_UID, POD_IP and
+// NODE_NAME). It adds nothing when not running in Kubernetes.
+func KubernetesEnricher() Enricher {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") == "" {
+		return &staticEnricher{}
+	}
+	return &staticEnricher{fields: fieldsFromEnv(map[string]string{
+		"POD_NAME":      "k8s.pod.name",
+		"POD_NAMESPACE": "k8s.namespace.name",
+		"POD_UID":       "k8s.pod.uid",
+		"POD_IP":        "k8s.pod.ip",
+		"NODE_NAME":     "k8s.node.name",
+	})}
+}
+
+// CloudEnricher adds cloud provider metadata read from the environment
+// variables set by AWS (Lambda, ECS), Google Cloud (Cloud Run, Functions)
+// and Azure App Service. The environment is read once, when the enricher is
+// created; no metadata endpoint is queried.
+func CloudEnricher() Enricher {
+	var fields map[string]interface{}
+
+	switch {
+	case os.Getenv("AWS_REGION") != "" || os.Getenv("AWS_EXECUTION_ENV") != "":
+		fields = fieldsFromEnv(map[string]string{
+			"AWS_REGION":                  "cloud.region",
+			"AWS_EXECUTION_ENV":           "cloud.platform",
+			"AWS_LAMBDA_FUNCTION_NAME":    "faas.name",
+			"AWS_LAMBDA_FUNCTION_VERSION": "faas.version",
+		})
+		fields["cloud.provider"] = "aws"
+	case os.Getenv("K_SERVICE") != "" || os.Getenv("GOOGLE_CLOUD_PROJECT") != "":
+		fields = fieldsFromEnv(map[string]string{
+			"GOOGLE_CLOUD_PROJECT": "cloud.account.id",
+			"K_SERVICE":            "faas.name",
+			"K_REVISION":           "faas.version",
+		})
+		fields["cloud.provider"] = "gcp"
+	case os.Getenv("WEBSITE_SITE_NAME") != "":
+		fields = fieldsFromEnv(map[string]string{
+			"WEBSITE_SITE_NAME":   "faas.name",
+			"REGION_NAME":         "cloud.region",
+			"WEBSITE_INSTANCE_ID": "faas.instance",
+		})
+		fields["cloud.provider"] = "azure"
+	}
+
+	return &staticEnricher{fields: fields}
+}
@@ -0,0 +1,93 @@
+package checklogs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIsTimeoutErrorClassification(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(500 * time.Millisecond):
+		}
+	}))
+	defer slow.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closedURL := closed.URL
+	closed.Close()
+
+	tests := []struct {
+		name    string
+		baseURL string
+		timeout time.Duration
+		ctx     func() (context.Context, context.CancelFunc)
+		want    bool
+	}{
+		{
+			name:    "client timeout",
+			baseURL: slow.URL,
+			timeout: 50 * time.Millisecond,
+			ctx:     func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			want:    true,
+		},
+		{
+			name:    "context deadline",
+			baseURL: slow.URL,
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 50*time.Millisecond)
+			},
+			want: true,
+		},
+		{
+			name:    "context canceled",
+			baseURL: slow.URL,
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(50*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			want: false,
+		},
+		{
+			name:    "connection refused",
+			baseURL: closedURL,
+			ctx:     func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			want:    false,
+		},
+		{
+			name:    "server error",
+			baseURL: failing.URL,
+			ctx:     func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := NewLogger("test-key", &Options{BaseURL: tt.baseURL, Timeout: tt.timeout, ConsoleWriter: io.Discard})
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			err := logger.Info(ctx, "classified")
+			var e *CheckLogsError
+			if !errors.As(err, &e) {
+				t.Fatalf("Info error = %v, want a *CheckLogsError", err)
+			}
+			if got := e.IsTimeoutError(); got != tt.want {
+				t.Errorf("IsTimeoutError() = %v, want %v (error: %v)", got, tt.want, err)
+			}
+		})
+	}
+}
@@ -254,7 +254,10 @@ func exempleRetryQueue(apiKey string) {
 		Source: "retry-flush",
 	})
 
-	// Simuler un flush (en réalité, on ne peut pas transférer la queue)
+	// Transférer la queue vers le nouveau logger puis flush
+	transferred := logger.TransferQueueTo(normalLogger)
+	fmt.Printf("📦 %d logs transférés vers le nouveau logger\n", transferred)
+
 	flushCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
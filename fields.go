@@ -0,0 +1,121 @@
+package checklogs
+
+import (
+	"context"
+	"time"
+)
+
+// Field is a typed key/value pair for structured logging. Fields are
+// collapsed into the log's context map. Fields created by NoConsole,
+// WithSource and WithTimestamp carry a call option instead.
+type Field struct {
+	Key   string
+	Value interface{}
+
+	option callOption
+}
+
+// String creates a string field
+func String(key, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int creates an integer field
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int64 creates a 64-bit integer field
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Float64 creates a floating point field
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Bool creates a boolean field
+func Bool(key string, value bool) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Duration creates a field holding a duration in milliseconds, the unit used
+// by timers
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Value: value.Milliseconds()}
+}
+
+// Time creates a field holding a time in RFC 3339 format
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, Value: value.Format(time.RFC3339Nano)}
+}
+
+// Err creates an "error" field holding the error message. A nil error adds
+// no field.
+func Err(err error) Field {
+	if err == nil {
+		return Field{}
+	}
+	return Field{Key: "error", Value: err.Error()}
+}
+
+// Any creates a field holding an arbitrary JSON-serializable value
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// splitFields collapses fields into a context map, later fields winning, and
+// collects their call options
+func splitFields(fields []Field) (map[string]interface{}, []callOption) {
+	context := make(map[string]interface{}, len(fields))
+	var opts []callOption
+	for _, field := range fields {
+		if field.option != nil {
+			opts = append(opts, field.option)
+		}
+		if field.Key != "" {
+			context[field.Key] = field.Value
+		}
+	}
+	return context, opts
+}
+
+// logFields logs a message with typed fields and call options
+func (l *Logger) logFields(ctx context.Context, level LogLevel, message string, fields []Field) error {
+	context, opts := splitFields(fields)
+	_, err := l.logResult(ctx, level, message, opts, context)
+	return err
+}
+
+// Debugw logs a debug message with typed fields. The call options
+// NoConsole, WithSource and WithTimestamp can be passed among the fields:
+//
+//	logger.Infow(ctx, "password reset", checklogs.String("user", id), checklogs.NoConsole())
+func (l *Logger) Debugw(ctx context.Context, message string, fields ...Field) error {
+	return l.logFields(ctx, Debug, message, fields)
+}
+
+// Infow logs an info message with typed fields and call options, like
+// Debugw
+func (l *Logger) Infow(ctx context.Context, message string, fields ...Field) error {
+	return l.logFields(ctx, Info, message, fields)
+}
+
+// Warningw logs a warning message with typed fields and call options, like
+// Debugw
+func (l *Logger) Warningw(ctx context.Context, message string, fields ...Field) error {
+	return l.logFields(ctx, Warning, message, fields)
+}
+
+// Errorw logs an error message with typed fields and call options, like
+// Debugw
+func (l *Logger) Errorw(ctx context.Context, message string, fields ...Field) error {
+	return l.logFields(ctx, Error, message, fields)
+}
+
+// Criticalw logs a critical message with typed fields and call options, like
+// Debugw
+func (l *Logger) Criticalw(ctx context.Context, message string, fields ...Field) error {
+	return l.logFields(ctx, Critical, message, fields)
+}
@@ -0,0 +1,112 @@
+package checklogs
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCallOptionsAsFields(t *testing.T) {
+	occurred := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		log           func(ctx context.Context, logger *Logger) error
+		wantLevel     LogLevel
+		wantContext   map[string]interface{}
+		wantSource    string
+		wantTimestamp time.Time
+		wantConsole   bool
+	}{
+		{
+			name: "fields only",
+			log: func(ctx context.Context, logger *Logger) error {
+				return logger.Infow(ctx, "event", String("user", "u1"))
+			},
+			wantLevel:   Info,
+			wantContext: map[string]interface{}{"user": "u1"},
+			wantSource:  "app",
+			wantConsole: true,
+		},
+		{
+			name: "NoConsole",
+			log: func(ctx context.Context, logger *Logger) error {
+				return logger.Warningw(ctx, "event", String("user", "u1"), NoConsole())
+			},
+			wantLevel:   Warning,
+			wantContext: map[string]interface{}{"user": "u1"},
+			wantSource:  "app",
+		},
+		{
+			name: "WithSource",
+			log: func(ctx context.Context, logger *Logger) error {
+				return logger.Errorw(ctx, "event", WithSource("migrations"))
+			},
+			wantLevel:   Error,
+			wantSource:  "migrations",
+			wantConsole: true,
+		},
+		{
+			name: "WithTimestamp",
+			log: func(ctx context.Context, logger *Logger) error {
+				return logger.Criticalw(ctx, "event", Int("attempt", 2), WithTimestamp(occurred))
+			},
+			wantLevel:     Critical,
+			wantContext:   map[string]interface{}{"attempt": float64(2)},
+			wantSource:    "app",
+			wantTimestamp: occurred,
+			wantConsole:   true,
+		},
+		{
+			name: "several options",
+			log: func(ctx context.Context, logger *Logger) error {
+				return logger.Debugw(ctx, "event", NoConsole(), WithSource("jobs"))
+			},
+			wantLevel:  Debug,
+			wantSource: "jobs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			var console bytes.Buffer
+			logger := newTestLogger(t, server, func(o *Options) {
+				o.Source = "app"
+				o.ConsoleOutput = true
+				o.ConsoleWriter = &console
+				o.MinLevel = Debug
+			})
+
+			if err := tt.log(context.Background(), logger); err != nil {
+				t.Fatalf("log: %v", err)
+			}
+
+			logs := server.logs()
+			if len(logs) != 1 {
+				t.Fatalf("server received %d logs, want 1", len(logs))
+			}
+			got := logs[0]
+			if got.Level != tt.wantLevel {
+				t.Errorf("level = %s, want %s", got.Level, tt.wantLevel)
+			}
+			if len(got.Context) != 0 || len(tt.wantContext) != 0 {
+				if !reflect.DeepEqual(got.Context, tt.wantContext) {
+					t.Errorf("context = %v, want %v", got.Context, tt.wantContext)
+				}
+			}
+			if got.Source != tt.wantSource {
+				t.Errorf("source = %q, want %q", got.Source, tt.wantSource)
+			}
+			if !tt.wantTimestamp.IsZero() && !got.Timestamp.Equal(tt.wantTimestamp) {
+				t.Errorf("timestamp = %v, want %v", got.Timestamp, tt.wantTimestamp)
+			}
+			if printed := strings.Contains(console.String(), "event"); printed != tt.wantConsole {
+				t.Errorf("console output %q, want printed = %v", console.String(), tt.wantConsole)
+			}
+		})
+	}
+}
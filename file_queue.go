@@ -0,0 +1,206 @@
+package checklogs
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// maxFileRecordBytes bounds the size of one compressed record, so a corrupt
+// length header is detected instead of causing a huge allocation
+const maxFileRecordBytes = 16 * 1024 * 1024
+
+// FileRetryQueueOptions represents configuration for a file-backed retry queue
+type FileRetryQueueOptions struct {
+	Compress bool `json:"compress"`
+}
+
+// FileRetryQueue is a RetryQueue persisted to a file, so logs queued during
+// an outage survive a restart. Entries are appended as JSON lines or, with
+// Compress, as individually gzipped records framed by a 4-byte length. Each
+// record stands alone, so a record left half-written by a crash is dropped
+// when the file is opened without losing the others; both formats can be
+// read back from the same file.
+type FileRetryQueue struct {
+	mutex    sync.Mutex
+	path     string
+	compress bool
+	count    int
+}
+
+// NewFileRetryQueue opens or creates the queue file at path, counting the
+// entries already in it and discarding any incomplete record at its end
+func NewFileRetryQueue(path string, opts *FileRetryQueueOptions) (*FileRetryQueue, error) {
+	q := &FileRetryQueue{path: path}
+	if opts != nil {
+		q.compress = opts.Compress
+	}
+
+	entries, validSize, err := q.read()
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > validSize {
+		if err := os.Truncate(path, validSize); err != nil {
+			return nil, err
+		}
+	}
+	q.count = len(entries)
+	return q, nil
+}
+
+func (q *FileRetryQueue) Add(data LogData) error {
+	record, err := q.encode(data)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	file, err := os.OpenFile(q.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// A single write keeps the record contiguous
+	if _, err := file.Write(record); err != nil {
+		return err
+	}
+	q.count++
+	return nil
+}
+
+func (q *FileRetryQueue) Drain() []LogData {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	entries, _, err := q.read()
+	if err != nil {
+		return nil
+	}
+	if err := os.Truncate(q.path, 0); err != nil && !os.IsNotExist(err) {
+		// Leave the file alone rather than hand out entries twice
+		return nil
+	}
+	q.count = 0
+	return entries
+}
+
+func (q *FileRetryQueue) Len() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.count
+}
+
+func (q *FileRetryQueue) Clear() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if err := os.Truncate(q.path, 0); err == nil || os.IsNotExist(err) {
+		q.count = 0
+	}
+}
+
+// encode serializes one entry as a JSON line or a framed gzip record
+func (q *FileRetryQueue) encode(data LogData) ([]byte, error) {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, &CheckLogsError{Type: "SerializationError", Message: err.Error()}
+	}
+	if !q.compress {
+		return append(jsonData, '\n'), nil
+	}
+
+	var buf bytes.Buffer
+	buf.Write(make([]byte, 4))
+	gz := gzip.NewWriter(&buf)
+	gz.Write(jsonData)
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	record := buf.Bytes()
+	binary.BigEndian.PutUint32(record, uint32(len(record)-4))
+	return record, nil
+}
+
+// read decodes every complete record in the file. It returns the entries
+// and the size of the file up to the end of the last complete record;
+// records that are complete but cannot be decoded are skipped.
+func (q *FileRetryQueue) read() ([]LogData, int64, error) {
+	file, err := os.Open(q.path)
+	if os.IsNotExist(err) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+
+	var entries []LogData
+	var offset int64
+	reader := bufio.NewReader(file)
+
+	for {
+		first, err := reader.Peek(1)
+		if err != nil {
+			break
+		}
+
+		// JSON line
+		if first[0] == '{' {
+			line, err := reader.ReadBytes('\n')
+			if err != nil {
+				break // incomplete last line
+			}
+			offset += int64(len(line))
+			var data LogData
+			if json.Unmarshal(line, &data) == nil {
+				entries = append(entries, data)
+			}
+			continue
+		}
+
+		// Length-framed gzip record
+		var header [4]byte
+		if _, err := io.ReadFull(reader, header[:]); err != nil {
+			break
+		}
+		size := binary.BigEndian.Uint32(header[:])
+		if size > maxFileRecordBytes {
+			break
+		}
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(reader, payload); err != nil {
+			break
+		}
+		offset += int64(len(header)) + int64(size)
+		if data, ok := decodeGzipRecord(payload); ok {
+			entries = append(entries, data)
+		}
+	}
+
+	return entries, offset, nil
+}
+
+// decodeGzipRecord decompresses and decodes one gzip record
+func decodeGzipRecord(payload []byte) (LogData, bool) {
+	var data LogData
+	gz, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		return data, false
+	}
+	defer gz.Close()
+
+	jsonData, err := io.ReadAll(gz)
+	if err != nil || json.Unmarshal(jsonData, &data) != nil {
+		return data, false
+	}
+	return data, true
+}
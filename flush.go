@@ -0,0 +1,91 @@
+package checklogs
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// flushDebouncer flushes the retry queue once failures have settled: each
+// enqueue restarts the delay, so a burst of failures is retried together
+// after the burst instead of midway through it
+type flushDebouncer struct {
+	mutex    sync.Mutex
+	delay    time.Duration
+	jitter   time.Duration
+	flush    func()
+	timer    *time.Timer
+	flushing bool
+	stopped  bool
+}
+
+// newFlushDebouncer creates a debouncer that calls flush after delay. The
+// first flush waits an extra jitter.
+func newFlushDebouncer(delay, jitter time.Duration, flush func()) *flushDebouncer {
+	return &flushDebouncer{delay: delay, jitter: jitter, flush: flush}
+}
+
+// trigger restarts the delay. Logs queued again by the flush itself do not
+// re-arm the timer, so an unreachable API is not retried in a tight loop.
+func (d *flushDebouncer) trigger() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.stopped || d.flushing {
+		return
+	}
+	if d.timer == nil {
+		d.timer = time.AfterFunc(d.delay+d.jitter, d.run)
+		return
+	}
+	d.timer.Reset(d.delay)
+}
+
+// run performs a debounced flush
+func (d *flushDebouncer) run() {
+	d.mutex.Lock()
+	if d.stopped {
+		d.mutex.Unlock()
+		return
+	}
+	d.flushing = true
+	d.mutex.Unlock()
+
+	d.flush()
+
+	d.mutex.Lock()
+	d.flushing = false
+	d.mutex.Unlock()
+}
+
+// stop cancels any pending flush and disables the debouncer
+func (d *flushDebouncer) stop() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.stopped = true
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+}
+
+// newDebouncer returns the logger's flush debouncer, or nil when
+// DebounceFlush is disabled
+func (l *Logger) newDebouncer() *flushDebouncer {
+	if l.options.DebounceFlush <= 0 {
+		return nil
+	}
+	return newFlushDebouncer(l.options.DebounceFlush, l.startupJitter(), func() {
+		l.FlushRetryQueue(context.Background())
+	})
+}
+
+// startupJitter returns a random delay up to StartupJitter, spreading the
+// first background action of instances started at the same time
+func (l *Logger) startupJitter() time.Duration {
+	if l.options.StartupJitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(l.options.StartupJitter)))
+}
@@ -0,0 +1,86 @@
+package checklogs
+
+import (
+	"context"
+	"sync"
+)
+
+// loggerContextKey is the context key of the logger stored by NewContext
+type loggerContextKey struct{}
+
+// NewContext returns a copy of ctx carrying logger, retrievable with
+// FromContext
+func NewContext(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// FromContext returns the logger stored in ctx by NewContext, or nil when
+// there is none
+func FromContext(ctx context.Context) *Logger {
+	logger, _ := ctx.Value(loggerContextKey{}).(*Logger)
+	return logger
+}
+
+// GroupError reports which task of a LoggingGroup failed first
+type GroupError struct {
+	Task string
+	Err  error
+}
+
+func (e *GroupError) Error() string {
+	return "task " + e.Task + ": " + e.Err.Error()
+}
+
+// Unwrap returns the task's error
+func (e *GroupError) Unwrap() error {
+	return e.Err
+}
+
+// LoggingGroup runs related tasks concurrently with errgroup semantics: the
+// first task to fail cancels the group's context and its error is returned
+// by Wait. Each task gets a child logger tagged with its name.
+type LoggingGroup struct {
+	logger *Logger
+	parent context.Context
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	errOnce sync.Once
+	err     error
+}
+
+// Group returns a new LoggingGroup and the context it derives from ctx, which
+// is cancelled when a task fails or Wait returns
+func (l *Logger) Group(ctx context.Context) (*LoggingGroup, context.Context) {
+	groupCtx, cancel := context.WithCancel(ctx)
+	return &LoggingGroup{logger: l, parent: ctx, ctx: groupCtx, cancel: cancel}, groupCtx
+}
+
+// Go runs fn in a new goroutine. The context passed to fn carries a child
+// logger with a "task" context field set to name, available through
+// FromContext. A failure is logged at the Error level through that logger.
+func (g *LoggingGroup) Go(name string, fn func(ctx context.Context) error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		logger := g.logger.Child(map[string]interface{}{"task": name})
+		if err := fn(NewContext(g.ctx, logger)); err != nil {
+			// Log against the parent context, the group's is being cancelled
+			logger.Error(g.parent, "Task failed", map[string]interface{}{"error": err.Error()})
+			g.errOnce.Do(func() {
+				g.err = &GroupError{Task: name, Err: err}
+				g.cancel()
+			})
+		}
+	}()
+}
+
+// Wait blocks until every task has returned, then returns the first
+// failure as a *GroupError, or nil
+func (g *LoggingGroup) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+	return g.err
+}
@@ -0,0 +1,111 @@
+package checklogs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultHeartbeatInterval is used when Heartbeat is given a non-positive interval
+	DefaultHeartbeatInterval = time.Minute
+	// DefaultHeartbeatMessage is the message of heartbeat logs unless overridden
+	DefaultHeartbeatMessage = "Heartbeat"
+)
+
+// Heartbeat logs a startup entry describing the current process, then emits
+// a heartbeat log every interval until stop is called or ctx is cancelled.
+// The heartbeat message, level and context come from Options. With
+// StartupJitter, the startup entry and first heartbeat wait a random delay
+// so a fleet of instances started together does not log in lockstep.
+func (l *Logger) Heartbeat(ctx context.Context, interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		interval = DefaultHeartbeatInterval
+	}
+
+	started := time.Now()
+	jitter := l.startupJitter()
+	if jitter == 0 {
+		l.Info(ctx, "Process started", l.processInfo())
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		if jitter > 0 {
+			timer := time.NewTimer(jitter)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			l.Info(ctx, "Process started", l.processInfo())
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				l.heartbeat(ctx, started)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cancel()
+			<-done
+		})
+	}
+}
+
+// heartbeat emits a single heartbeat log
+func (l *Logger) heartbeat(ctx context.Context, started time.Time) {
+	message := l.options.HeartbeatMessage
+	if message == "" {
+		message = DefaultHeartbeatMessage
+	}
+	level := l.options.HeartbeatLevel
+	if level == "" {
+		level = Info
+	}
+
+	context := map[string]interface{}{
+		"uptime_seconds": int64(time.Since(started).Seconds()),
+	}
+	for k, v := range l.options.HeartbeatContext {
+		context[k] = v
+	}
+
+	l.log(ctx, level, message, context)
+}
+
+// processInfo describes the running process for the startup log
+func (l *Logger) processInfo() map[string]interface{} {
+	info := map[string]interface{}{
+		"pid":        os.Getpid(),
+		"process":    filepath.Base(os.Args[0]),
+		"go_version": runtime.Version(),
+		"os":         runtime.GOOS,
+		"arch":       runtime.GOARCH,
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		info["hostname"] = hostname
+	}
+	for k, v := range l.options.HeartbeatContext {
+		info[k] = v
+	}
+	return info
+}
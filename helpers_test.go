@@ -0,0 +1,103 @@
+package checklogs
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testRequest is one request received by a testServer
+type testRequest struct {
+	Path        string
+	ContentType string
+	Logs        []LogData
+}
+
+// testServer is a fake CheckLogs API recording the logs posted to it
+type testServer struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	status   int
+	requests []testRequest
+}
+
+// newTestServer starts a fake API answering every request with 200 until
+// setStatus changes it
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	s := &testServer{status: http.StatusOK}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *testServer) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	req := testRequest{Path: r.URL.Path, ContentType: r.Header.Get("Content-Type")}
+
+	switch {
+	case r.URL.Path == "/api/logs/batch":
+		var batch batchRequest
+		json.Unmarshal(body, &batch)
+		req.Logs = batch.Logs
+	case strings.HasPrefix(req.ContentType, "application/json"):
+		var data LogData
+		if json.Unmarshal(body, &data) == nil {
+			req.Logs = []LogData{data}
+		}
+	default:
+		// Multipart uploads are recorded without their logs
+	}
+
+	s.mutex.Lock()
+	status := s.status
+	if status < 300 {
+		s.requests = append(s.requests, req)
+	}
+	s.mutex.Unlock()
+
+	w.WriteHeader(status)
+	w.Write([]byte(`{"id":"log-1"}`))
+}
+
+// setStatus changes the status of the following responses; requests
+// answered with an error status are not recorded
+func (s *testServer) setStatus(status int) {
+	s.mutex.Lock()
+	s.status = status
+	s.mutex.Unlock()
+}
+
+// received returns the requests accepted so far
+func (s *testServer) received() []testRequest {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]testRequest(nil), s.requests...)
+}
+
+// logs returns the logs accepted so far, across all requests
+func (s *testServer) logs() []LogData {
+	var logs []LogData
+	for _, req := range s.received() {
+		logs = append(logs, req.Logs...)
+	}
+	return logs
+}
+
+// newTestLogger creates a quiet logger sending to server, with opts applied
+// over the test defaults
+func newTestLogger(t *testing.T, server *testServer, configure func(*Options)) *Logger {
+	t.Helper()
+	opts := &Options{
+		BaseURL:       server.URL,
+		ConsoleWriter: io.Discard,
+	}
+	if configure != nil {
+		configure(opts)
+	}
+	return NewLogger("test-key", opts)
+}
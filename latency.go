@@ -0,0 +1,73 @@
+package checklogs
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencySampleSize is the number of recent send durations kept for
+// percentile estimates
+const latencySampleSize = 1024
+
+// latencyReservoir keeps the most recent send durations in a ring buffer
+type latencyReservoir struct {
+	mutex   sync.Mutex
+	samples []time.Duration
+	next    int
+}
+
+// newLatencyReservoir creates an empty latency reservoir
+func newLatencyReservoir() *latencyReservoir {
+	return &latencyReservoir{samples: make([]time.Duration, 0, latencySampleSize)}
+}
+
+// record adds a sample, overwriting the oldest once the reservoir is full
+func (r *latencyReservoir) record(d time.Duration) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if len(r.samples) < latencySampleSize {
+		r.samples = append(r.samples, d)
+		return
+	}
+	r.samples[r.next] = d
+	r.next = (r.next + 1) % latencySampleSize
+}
+
+// percentiles returns the requested percentiles (0-100) of the samples,
+// using the nearest-rank method, or zeros when there are no samples
+func (r *latencyReservoir) percentiles(ps ...float64) []time.Duration {
+	r.mutex.Lock()
+	sorted := make([]time.Duration, len(r.samples))
+	copy(sorted, r.samples)
+	r.mutex.Unlock()
+
+	result := make([]time.Duration, len(ps))
+	if len(sorted) == 0 {
+		return result
+	}
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	for i, p := range ps {
+		rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+		if rank < 0 {
+			rank = 0
+		}
+		if rank >= len(sorted) {
+			rank = len(sorted) - 1
+		}
+		result[i] = sorted[rank]
+	}
+	return result
+}
+
+// LatencyPercentiles returns the 50th, 95th and 99th percentile durations
+// of recent requests to the logs endpoint, measured from request start until
+// the response headers arrive. Only requests that got a response are
+// counted; it returns zeros until the first one completes.
+func (l *Logger) LatencyPercentiles() (p50, p95, p99 time.Duration) {
+	values := l.latency.percentiles(50, 95, 99)
+	return values[0], values[1], values[2]
+}
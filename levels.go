@@ -0,0 +1,127 @@
+package checklogs
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// Severity returns the rank of a level, from 1 for Debug to 5 for
+// Critical, or 0 for an unknown level
+func (level LogLevel) Severity() int {
+	switch level {
+	case Debug:
+		return 1
+	case Info:
+		return 2
+	case Warning:
+		return 3
+	case Error:
+		return 4
+	case Critical:
+		return 5
+	default:
+		return 0
+	}
+}
+
+// MarshalJSON encodes the level as its lowercase name
+func (level LogLevel) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(level))
+}
+
+// UnmarshalJSON decodes a level name. Unknown names are kept as they are,
+// so a server response or stored log with a level this SDK does not know
+// still decodes; such a log fails validation when it is sent. Options
+// decoding checks its levels strictly.
+func (level *LogLevel) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return &CheckLogsError{Type: "ValidationError", Message: "log level must be a string: " + string(b)}
+	}
+	*level = LogLevel(s)
+	return nil
+}
+
+// levelFilter holds the levels a logger and its children emit, which can
+// change at runtime
+type levelFilter struct {
+	mutex   sync.RWMutex
+	enabled []LogLevel
+	min     LogLevel
+}
+
+// newLevelFilter creates a filter from the configured levels
+func newLevelFilter(enabled []LogLevel, min LogLevel) *levelFilter {
+	return &levelFilter{enabled: append([]LogLevel(nil), enabled...), min: min}
+}
+
+// isLevelEnabled reports whether logs at level pass the MinLevel filter or,
+// when MinLevel is unset, the EnabledLevels filter. An empty EnabledLevels
+// enables every level.
+func (l *Logger) isLevelEnabled(level LogLevel) bool {
+	f := l.levels
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	if f.min != "" {
+		return level.Severity() >= f.min.Severity()
+	}
+	if len(f.enabled) == 0 {
+		return true
+	}
+	for _, enabled := range f.enabled {
+		if enabled == level {
+			return true
+		}
+	}
+	return false
+}
+
+// SetEnabledLevels changes the levels emitted by the logger and its
+// children while they are in use, for example to turn on Debug logs
+// temporarily. It clears any minimum level; an empty list enables every
+// level.
+func (l *Logger) SetEnabledLevels(levels []LogLevel) error {
+	for _, level := range levels {
+		if !IsValidLevel(level) {
+			return &CheckLogsError{Type: "ValidationError", Message: "invalid log level: " + string(level)}
+		}
+	}
+
+	f := l.levels
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.enabled = append([]LogLevel(nil), levels...)
+	f.min = ""
+	return nil
+}
+
+// SetMinLevel changes the minimum level emitted by the logger and its
+// children while they are in use. An empty level removes the minimum,
+// falling back to the enabled levels.
+func (l *Logger) SetMinLevel(level LogLevel) error {
+	if level != "" && !IsValidLevel(level) {
+		return &CheckLogsError{Type: "ValidationError", Message: "invalid log level: " + string(level)}
+	}
+
+	f := l.levels
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.min = level
+	return nil
+}
+
+// IsEnabled reports whether a log at level would be emitted anywhere, so
+// callers can skip building expensive context for logs that would be
+// discarded:
+//
+//	if logger.IsEnabled(checklogs.Debug) {
+//		logger.Debug(ctx, "cache state", cache.Dump())
+//	}
+func (l *Logger) IsEnabled(level LogLevel) bool {
+	if l.closed.Load() || !l.isLevelEnabled(level) {
+		return false
+	}
+	// Silent disables the console and the API, leaving only sinks
+	return !l.options.Silent || len(l.options.Sinks) > 0
+}
@@ -0,0 +1,92 @@
+package checklogs
+
+import (
+	"context"
+	"sync"
+)
+
+const defaultMaxSourceLength = 100
+
+// ServerLimits are the size limits the API enforces on each log entry, which
+// may differ by plan. A zero value means the limit is not enforced, except for
+// the limits that always have a default.
+type ServerLimits struct {
+	MaxMessageLength int `json:"max_message_length"`
+	MaxSourceLength  int `json:"max_source_length"`
+	MaxContextBytes  int `json:"max_context_bytes"`
+	MaxDetailBytes   int `json:"max_detail_bytes"`
+	MaxContextKeys   int `json:"max_context_keys"`
+}
+
+// logLimits holds the limits used by client-side validation, which can be
+// updated at runtime from the server
+type logLimits struct {
+	mutex   sync.RWMutex
+	current ServerLimits
+}
+
+// newLogLimits creates limits from the defaults and the logger's options
+func newLogLimits(options Options) *logLimits {
+	return &logLimits{current: ServerLimits{
+		MaxMessageLength: options.MaxMessageLength,
+		MaxSourceLength:  defaultMaxSourceLength,
+		MaxContextBytes:  options.MaxContextBytes,
+		MaxDetailBytes:   options.MaxDetailBytes,
+		MaxContextKeys:   options.MaxContextKeys,
+	}}
+}
+
+// get returns the limits in effect
+func (l *logLimits) get() ServerLimits {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return l.current
+}
+
+// apply overrides the limits with the non-zero values of limits
+func (l *logLimits) apply(limits ServerLimits) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if limits.MaxMessageLength > 0 {
+		l.current.MaxMessageLength = limits.MaxMessageLength
+	}
+	if limits.MaxSourceLength > 0 {
+		l.current.MaxSourceLength = limits.MaxSourceLength
+	}
+	if limits.MaxContextBytes > 0 {
+		l.current.MaxContextBytes = limits.MaxContextBytes
+	}
+	if limits.MaxDetailBytes > 0 {
+		l.current.MaxDetailBytes = limits.MaxDetailBytes
+	}
+	if limits.MaxContextKeys > 0 {
+		l.current.MaxContextKeys = limits.MaxContextKeys
+	}
+}
+
+// FetchLimits fetches the per-entry limits the API enforces for this API key
+func (l *Logger) FetchLimits(ctx context.Context) (*ServerLimits, error) {
+	var limits ServerLimits
+	if err := l.requestJSON(ctx, "GET", "/api/limits", nil, &limits); err != nil {
+		return nil, err
+	}
+	return &limits, nil
+}
+
+// SyncLimits fetches the server's limits and applies them to client-side
+// validation, for this logger and its children, so that logs are accepted
+// or rejected locally exactly as the server would
+func (l *Logger) SyncLimits(ctx context.Context) error {
+	limits, err := l.FetchLimits(ctx)
+	if err != nil {
+		return err
+	}
+	l.limits.apply(*limits)
+	return nil
+}
+
+// Limits returns the limits currently used by client-side validation
+func (l *Logger) Limits() ServerLimits {
+	return l.limits.get()
+}
@@ -0,0 +1,244 @@
+package checklogs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RequestIDHeader is the header read and echoed by HTTPMiddleware to
+// correlate a request's logs
+const RequestIDHeader = "X-Request-ID"
+
+// statusRecorder captures the status code and body size written through a
+// ResponseWriter
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(p)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Flush sends buffered data to the client when the wrapped ResponseWriter
+// supports it, so streaming handlers keep working behind the middleware
+func (r *statusRecorder) Flush() {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the wrapped ResponseWriter for http.ResponseController
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// countingBody counts the bytes of a request body read by the handler
+type countingBody struct {
+	io.ReadCloser
+	bytes int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.bytes += int64(n)
+	return n, err
+}
+
+// MiddlewareOptions configures HTTPMiddlewareWithOptions
+type MiddlewareOptions struct {
+	// Route returns the route pattern that matched a request, such as
+	// "/users/{id}", logged as "route". It is called once the handler has
+	// returned, with the request passed to it, so a router that records the
+	// pattern on the request or its context can report it. Nil leaves the
+	// route out.
+	Route func(r *http.Request) string
+	// Exclude lists the fields left out of the logs, such as "query" or
+	// "client_ip"
+	Exclude []string
+	// TrustedProxies lists the addresses or CIDR ranges of the proxies in
+	// front of the app, such as "10.0.0.0/8". X-Forwarded-For is only read
+	// when the connection comes from one of them, and client_ip is then the
+	// rightmost address it lists that is not a trusted proxy. Empty, the
+	// default, ignores X-Forwarded-For: any client can set it.
+	TrustedProxies []string
+}
+
+// HTTPMiddleware logs every request handled by next. Each request gets a
+// child logger with request_id, method, path, query, remote_ip and
+// client_ip, stored in the request context for handlers to retrieve with
+// FromContext. Completion is logged with the status, duration in
+// milliseconds and request and response body sizes, at the Error level for
+// 5xx responses. The request ID is taken from the X-Request-ID header when
+// present, generated otherwise, and echoed in the response.
+func (l *Logger) HTTPMiddleware(next http.Handler) http.Handler {
+	return l.HTTPMiddlewareWithOptions(next, MiddlewareOptions{})
+}
+
+// HTTPMiddlewareWithOptions is HTTPMiddleware with a route pattern and a
+// choice of the fields logged
+func (l *Logger) HTTPMiddlewareWithOptions(next http.Handler, opts MiddlewareOptions) http.Handler {
+	excluded := make(map[string]struct{}, len(opts.Exclude))
+	for _, field := range opts.Exclude {
+		excluded[field] = struct{}{}
+	}
+	fields := func(all map[string]interface{}) map[string]interface{} {
+		for field := range excluded {
+			delete(all, field)
+		}
+		return all
+	}
+	trusted := l.parseTrustedProxies(opts.TrustedProxies)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+
+		remoteIP := r.RemoteAddr
+		if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+			remoteIP = host
+		}
+
+		requestContext := map[string]interface{}{
+			"request_id": requestID,
+			"method":     r.Method,
+			"path":       r.URL.Path,
+			"remote_ip":  remoteIP,
+			"client_ip":  clientIP(r, remoteIP, trusted),
+		}
+		if r.URL.RawQuery != "" {
+			requestContext["query"] = r.URL.RawQuery
+		}
+		logger := l.Child(fields(requestContext))
+
+		body := &countingBody{ReadCloser: r.Body}
+		if r.Body != nil && r.Body != http.NoBody {
+			r.Body = body
+		}
+		recorder := &statusRecorder{ResponseWriter: w}
+		r = r.WithContext(NewContext(r.Context(), logger))
+		next.ServeHTTP(recorder, r)
+
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		level := Info
+		if status >= 500 {
+			level = Error
+		}
+
+		completion := map[string]interface{}{
+			"status":         status,
+			"duration_ms":    time.Since(start).Milliseconds(),
+			"request_bytes":  body.bytes,
+			"response_bytes": recorder.bytes,
+		}
+		if opts.Route != nil {
+			if route := opts.Route(r); route != "" {
+				completion["route"] = route
+			}
+		}
+
+		// Log even when the client went away and cancelled the request
+		logger.log(context.WithoutCancel(r.Context()), level, "Request completed", fields(completion))
+	})
+}
+
+// parseTrustedProxies parses the entries of MiddlewareOptions.TrustedProxies,
+// reporting and skipping those that are neither an address nor a CIDR range
+func (l *Logger) parseTrustedProxies(proxies []string) []*net.IPNet {
+	var trusted []*net.IPNet
+	for _, proxy := range proxies {
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			trusted = append(trusted, network)
+			continue
+		}
+		ip := net.ParseIP(proxy)
+		if ip == nil {
+			if !l.options.Silent {
+				l.console.WriteString(fmt.Sprintf("[CHECKLOGS ERROR] middleware: trusted proxy %q is not an address or CIDR range\n", proxy))
+			}
+			continue
+		}
+		bits := 8 * net.IPv6len
+		if ip4 := ip.To4(); ip4 != nil {
+			ip, bits = ip4, 8*net.IPv4len
+		}
+		trusted = append(trusted, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+	}
+	return trusted
+}
+
+// isTrusted reports whether addr is in one of the trusted networks
+func isTrusted(addr string, trusted []*net.IPNet) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, network := range trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP returns the originating client address. X-Forwarded-For is only
+// followed when the connection comes from a trusted proxy: its addresses
+// are walked from the right, each appended by the hop before, and the first
+// one that is not a trusted proxy is the client. The connection's address
+// is returned otherwise.
+func clientIP(r *http.Request, remoteIP string, trusted []*net.IPNet) string {
+	if !isTrusted(remoteIP, trusted) {
+		return remoteIP
+	}
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	client := remoteIP
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		addr := strings.TrimSpace(forwarded[i])
+		if net.ParseIP(addr) == nil {
+			break
+		}
+		client = addr
+		if !isTrusted(addr, trusted) {
+			break
+		}
+	}
+	return client
+}
+
+// newRequestID returns a random 16-byte hex request ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,142 @@
+package checklogs
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPMiddlewareFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    MiddlewareOptions
+		target  string
+		header  map[string]string
+		body    string
+		want    map[string]interface{}
+		missing []string
+	}{
+		{
+			name:   "default fields",
+			target: "/orders?page=2",
+			body:   "payload",
+			want: map[string]interface{}{
+				"method":         "POST",
+				"path":           "/orders",
+				"query":          "page=2",
+				"remote_ip":      "192.0.2.1",
+				"client_ip":      "192.0.2.1",
+				"status":         float64(201),
+				"request_bytes":  float64(7),
+				"response_bytes": float64(5),
+			},
+			missing: []string{"route"},
+		},
+		{
+			name:   "X-Forwarded-For ignored without trusted proxies",
+			target: "/orders",
+			header: map[string]string{"X-Forwarded-For": "203.0.113.7"},
+			want: map[string]interface{}{
+				"remote_ip": "192.0.2.1",
+				"client_ip": "192.0.2.1",
+			},
+			missing: []string{"query"},
+		},
+		{
+			name:   "X-Forwarded-For ignored from an untrusted peer",
+			opts:   MiddlewareOptions{TrustedProxies: []string{"10.0.0.0/8"}},
+			target: "/orders",
+			header: map[string]string{"X-Forwarded-For": "203.0.113.7"},
+			want:   map[string]interface{}{"client_ip": "192.0.2.1"},
+		},
+		{
+			name:   "client IP from a trusted proxy",
+			opts:   MiddlewareOptions{TrustedProxies: []string{"192.0.2.1"}},
+			target: "/orders",
+			header: map[string]string{"X-Forwarded-For": "203.0.113.7"},
+			want:   map[string]interface{}{"remote_ip": "192.0.2.1", "client_ip": "203.0.113.7"},
+		},
+		{
+			name:   "spoofed entries left of the client skipped",
+			opts:   MiddlewareOptions{TrustedProxies: []string{"192.0.2.0/24", "10.0.0.0/8"}},
+			target: "/orders",
+			header: map[string]string{"X-Forwarded-For": "198.51.100.9, 203.0.113.7, 10.0.0.1"},
+			want:   map[string]interface{}{"client_ip": "203.0.113.7"},
+		},
+		{
+			name:   "route pattern",
+			opts:   MiddlewareOptions{Route: func(r *http.Request) string { return "/orders/{id}" }},
+			target: "/orders/42",
+			want:   map[string]interface{}{"route": "/orders/{id}", "path": "/orders/42"},
+		},
+		{
+			name:    "excluded fields",
+			opts:    MiddlewareOptions{Exclude: []string{"query", "client_ip", "request_bytes"}},
+			target:  "/orders?token=secret",
+			header:  map[string]string{"X-Forwarded-For": "203.0.113.7"},
+			want:    map[string]interface{}{"path": "/orders", "remote_ip": "192.0.2.1"},
+			missing: []string{"query", "client_ip", "request_bytes"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			logger := newTestLogger(t, server, nil)
+			handler := logger.HTTPMiddlewareWithOptions(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				io.ReadAll(r.Body)
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte("hello"))
+			}), tt.opts)
+
+			req := httptest.NewRequest("POST", tt.target, strings.NewReader(tt.body))
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			logs := server.logs()
+			if len(logs) != 1 {
+				t.Fatalf("server received %d logs, want 1", len(logs))
+			}
+			got := logs[0].Context
+			for k, want := range tt.want {
+				if got[k] != want {
+					t.Errorf("%s = %v, want %v", k, got[k], want)
+				}
+			}
+			for _, k := range tt.missing {
+				if v, ok := got[k]; ok {
+					t.Errorf("%s = %v, want it left out", k, v)
+				}
+			}
+			if _, ok := got["request_id"]; !ok {
+				t.Errorf("request_id missing from %v", got)
+			}
+		})
+	}
+}
+
+func TestHTTPMiddlewareFlush(t *testing.T) {
+	server := newTestServer(t)
+	logger := newTestLogger(t, server, nil)
+	handler := logger.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			t.Fatal("ResponseWriter does not implement http.Flusher")
+		}
+		flusher.Flush()
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/events", nil))
+
+	if !recorder.Flushed {
+		t.Error("Flush was not forwarded to the underlying ResponseWriter")
+	}
+	if logs := server.logs(); len(logs) != 1 || logs[0].Context["status"] != float64(http.StatusOK) {
+		t.Errorf("logs = %v, want one completion with status 200", logs)
+	}
+}
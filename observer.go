@@ -0,0 +1,154 @@
+package checklogs
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Event describes an internal SDK condition reported to Options.Observer.
+// Events are never sent to the CheckLogs API.
+type Event struct {
+	Type    string                 `json:"type"`
+	Level   LogLevel               `json:"level"`
+	Message string                 `json:"message"`
+	Context map[string]interface{} `json:"context,omitempty"`
+}
+
+// Event types
+const (
+	EventRetryQueueWatermark = "retry_queue_watermark"
+	EventContextKeyOverride  = "context_key_override"
+)
+
+// notify reports an event to the console and Options.Observer
+func (l *Logger) notify(event Event) {
+	if !l.options.Silent {
+		l.console.WriteString(fmt.Sprintf("[CHECKLOGS %s] %s\n", strings.ToUpper(string(event.Level)), event.Message))
+	}
+	if l.options.Observer != nil {
+		l.options.Observer(event)
+	}
+}
+
+// reportContextOverride records that layer set key, reporting the layer it
+// overrode if the key was already set
+func (l *Logger) reportContextOverride(key string, origins map[string]string, layer string) {
+	if previous, ok := origins[key]; ok {
+		l.notify(Event{
+			Type:    EventContextKeyOverride,
+			Level:   Warning,
+			Message: fmt.Sprintf("context key %q from %s overridden by %s", key, previous, layer),
+			Context: map[string]interface{}{
+				"key":              key,
+				"overridden_layer": previous,
+				"overriding_layer": layer,
+			},
+		})
+	}
+	origins[key] = layer
+}
+
+// watermarkState remembers which retry queue watermarks have already been
+// reported so each crossing is announced only once
+type watermarkState struct {
+	mutex      sync.Mutex
+	watermarks []int
+	reported   map[int]bool
+}
+
+// retryQueueWatermarks returns the watermarks of the retry queue: the
+// RetryQueueWatermarkRatios of MaxRetryQueueSize for the bounded queue, or
+// the absolute RetryQueueWatermarks otherwise
+func retryQueueWatermarks(options Options) []int {
+	if options.MaxRetryQueueSize <= 0 {
+		return options.RetryQueueWatermarks
+	}
+	watermarks := make([]int, 0, len(options.RetryQueueWatermarkRatios))
+	for _, ratio := range options.RetryQueueWatermarkRatios {
+		if ratio > 0 && ratio <= 1 {
+			watermarks = append(watermarks, int(math.Max(1, math.Ceil(ratio*float64(options.MaxRetryQueueSize)))))
+		}
+	}
+	return watermarks
+}
+
+// newWatermarkState creates the watermark tracker, or nil when no
+// watermarks are configured
+func newWatermarkState(watermarks []int) *watermarkState {
+	if len(watermarks) == 0 {
+		return nil
+	}
+	sorted := make([]int, len(watermarks))
+	copy(sorted, watermarks)
+	sort.Ints(sorted)
+	return &watermarkState{
+		watermarks: sorted,
+		reported:   make(map[int]bool),
+	}
+}
+
+// reportRetryQueueWatermarks reports the highest newly crossed watermark for
+// the given queue size. A watermark is reported once until it is re-armed.
+func (l *Logger) reportRetryQueueWatermarks(size int) {
+	state := l.watermarks
+	if state == nil {
+		return
+	}
+
+	state.mutex.Lock()
+	crossed := 0
+	highest := state.watermarks[len(state.watermarks)-1]
+	for _, mark := range state.watermarks {
+		if size >= mark && !state.reported[mark] {
+			state.reported[mark] = true
+			crossed = mark
+		}
+	}
+	state.mutex.Unlock()
+
+	if crossed == 0 {
+		return
+	}
+
+	level := Warning
+	if crossed == highest {
+		level = Critical
+	}
+	message := fmt.Sprintf("Retry queue holds %d logs (watermark %d)", size, crossed)
+	context := map[string]interface{}{
+		"size":      size,
+		"watermark": crossed,
+	}
+	if capacity := l.options.MaxRetryQueueSize; capacity > 0 {
+		message = fmt.Sprintf("Retry queue holds %d of %d logs (watermark %d)", size, capacity, crossed)
+		context["capacity"] = capacity
+	}
+	l.notify(Event{
+		Type:    EventRetryQueueWatermark,
+		Level:   level,
+		Message: message,
+		Context: context,
+	})
+}
+
+// rearmRetryQueueWatermarks re-arms the watermarks above the given queue
+// size. It is called once a flush or clear has settled, not while a flush
+// drains and requeues the queue, so an outage that keeps the queue full is
+// reported only once.
+func (l *Logger) rearmRetryQueueWatermarks(size int) {
+	state := l.watermarks
+	if state == nil {
+		return
+	}
+
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	for _, mark := range state.watermarks {
+		if size < mark {
+			state.reported[mark] = false
+		}
+	}
+}
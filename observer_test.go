@@ -0,0 +1,113 @@
+package checklogs
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// eventRecorder collects the events reported to Options.Observer
+type eventRecorder struct {
+	mutex  sync.Mutex
+	events []Event
+}
+
+func (r *eventRecorder) observe(event Event) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *eventRecorder) watermarks() []Event {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var events []Event
+	for _, event := range r.events {
+		if event.Type == EventRetryQueueWatermark {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func TestRetryQueueWatermarkEvent(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxSize     int
+		watermarks  []int
+		ratios      []float64
+		wantContext map[string]interface{}
+	}{
+		{"absolute watermark on the unbounded queue", 0, []int{2}, nil, map[string]interface{}{"size": 2, "watermark": 2}},
+		{"ratio of the bounded queue", 10, nil, []float64{0.2}, map[string]interface{}{"size": 2, "watermark": 2, "capacity": 10}},
+		{"ratio rounded up", 6, nil, []float64{0.25}, map[string]interface{}{"size": 2, "watermark": 2, "capacity": 6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			var recorder eventRecorder
+			logger := newTestLogger(t, server, func(o *Options) {
+				o.MaxRetryQueueSize = tt.maxSize
+				o.RetryQueueWatermarks = tt.watermarks
+				o.RetryQueueWatermarkRatios = tt.ratios
+				o.Observer = recorder.observe
+			})
+
+			server.setStatus(http.StatusServiceUnavailable)
+			logger.Error(context.Background(), "outage")
+			logger.Error(context.Background(), "outage")
+
+			events := recorder.watermarks()
+			if len(events) != 1 {
+				t.Fatalf("observed %d watermark events, want 1", len(events))
+			}
+			if !reflect.DeepEqual(events[0].Context, tt.wantContext) {
+				t.Errorf("event context = %v, want %v", events[0].Context, tt.wantContext)
+			}
+		})
+	}
+}
+
+func TestRetryQueueWatermarkRearm(t *testing.T) {
+	tests := []struct {
+		name        string
+		flushStatus int
+		wantEvents  int
+	}{
+		{"failing flushes do not re-report", http.StatusServiceUnavailable, 1},
+		{"delivered flush re-arms", http.StatusOK, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			server := newTestServer(t)
+			var recorder eventRecorder
+			logger := newTestLogger(t, server, func(o *Options) {
+				o.MaxRetryQueueSize = 4
+				o.RetryQueueWatermarkRatios = []float64{0.5}
+				o.Observer = recorder.observe
+			})
+
+			server.setStatus(http.StatusServiceUnavailable)
+			logger.Error(ctx, "outage")
+			logger.Error(ctx, "outage")
+
+			server.setStatus(tt.flushStatus)
+			for i := 0; i < 3; i++ {
+				logger.FlushRetryQueue(ctx)
+			}
+
+			server.setStatus(http.StatusServiceUnavailable)
+			logger.Error(ctx, "outage")
+			logger.Error(ctx, "outage")
+
+			if got := len(recorder.watermarks()); got != tt.wantEvents {
+				t.Errorf("observed %d watermark events, want %d", got, tt.wantEvents)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package checklogs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Validate checks the options for values out of range and for combinations
+// that cannot work, reporting every problem found in a single
+// ConfigurationError. Zero values are valid and mean "use the default".
+func (o *Options) Validate() error {
+	var problems []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	if o.BaseURL != "" {
+		if err := validateURL(o.BaseURL); err != nil {
+			problems = append(problems, "BaseURL: "+err.Error())
+		}
+	}
+
+	for _, endpoint := range o.Endpoints {
+		if err := validateURL(endpoint.URL); err != nil {
+			problems = append(problems, "Endpoints: "+err.Error())
+		}
+		check(endpoint.Weight >= 0, "Endpoints: weight of %q must not be negative", endpoint.URL)
+	}
+
+	check(o.Timeout >= 0, "Timeout must not be negative")
+	check(o.BaseURLCacheTTL >= 0, "BaseURLCacheTTL must not be negative")
+	check(o.DebounceFlush >= 0, "DebounceFlush must not be negative")
+	check(o.ErrorThrottleWindow >= 0, "ErrorThrottleWindow must not be negative")
+	check(o.StartupJitter >= 0, "StartupJitter must not be negative")
+	check(o.MaxContextKeys >= 0, "MaxContextKeys must not be negative")
+	check(o.MaxDetailBytes >= 0, "MaxDetailBytes must not be negative")
+	check(o.MaxMessageLength >= 0, "MaxMessageLength must not be negative")
+	check(o.MaxContextBytes >= 0, "MaxContextBytes must not be negative")
+	check(o.MaxValueBytes >= 0, "MaxValueBytes must not be negative")
+	check(o.MaxResponseBytes >= 0, "MaxResponseBytes must not be negative")
+	check(o.MaxAttachmentBytes >= 0, "MaxAttachmentBytes must not be negative")
+	check(o.MaxEntryBytes >= 0, "MaxEntryBytes must not be negative")
+	check(o.RetryInterval >= 0, "RetryInterval must not be negative")
+	check(o.MaxRetries >= 0, "MaxRetries must not be negative")
+	check(o.RetryBaseDelay >= 0, "RetryBaseDelay must not be negative")
+	check(o.MaxStackTraceBytes >= 0, "MaxStackTraceBytes must not be negative")
+	check(o.MaxRetryQueueSize >= 0, "MaxRetryQueueSize must not be negative")
+	check(o.DropPolicy == DropOldest || o.DropPolicy == DropNewest, "DropPolicy %d is not a valid policy", o.DropPolicy)
+	check(o.BufferSize >= 0, "BufferSize must not be negative")
+	check(o.FlushInterval >= 0, "FlushInterval must not be negative")
+	check(o.SampleRate >= 0 && o.SampleRate <= 1, "SampleRate must be between 0 and 1, got %g", o.SampleRate)
+	check(o.HeartbeatLevel == "" || IsValidLevel(o.HeartbeatLevel), "HeartbeatLevel %q is not a valid level", o.HeartbeatLevel)
+	check(o.OnCanceledContext == CanceledContextQueue || o.OnCanceledContext == CanceledContextReturn,
+		"OnCanceledContext %d is not a valid policy", o.OnCanceledContext)
+	check(o.MinLevel == "" || IsValidLevel(o.MinLevel), "MinLevel %q is not a valid level", o.MinLevel)
+	for _, level := range o.EnabledLevels {
+		check(IsValidLevel(level), "EnabledLevels: %q is not a valid level", level)
+	}
+	check(o.ConsoleFormat == "" || o.ConsoleFormat == ConsoleFormatText || o.ConsoleFormat == ConsoleFormatJSON, "ConsoleFormat %q is not a valid console format", o.ConsoleFormat)
+	for level, format := range o.ConsoleFormatByLevel {
+		check(format == ConsoleFormatText || format == ConsoleFormatJSON, "ConsoleFormatByLevel[%s] %q is not a valid console format", level, format)
+	}
+	for level, rate := range o.SampleRates {
+		check(rate >= 0 && rate <= 1, "SampleRates[%s] must be between 0 and 1, got %g", level, rate)
+	}
+	if a := o.AdaptiveSampling; a != nil {
+		check(a.Floor >= 0 && a.Floor <= a.Ceiling && a.Ceiling <= 1, "AdaptiveSampling requires 0 <= Floor <= Ceiling <= 1")
+		check(a.ErrorRateThreshold > 0 && a.ErrorRateThreshold <= 1, "AdaptiveSampling.ErrorRateThreshold must be between 0 and 1")
+		check(a.Interval >= 0, "AdaptiveSampling.Interval must not be negative")
+	}
+	for _, watermark := range o.RetryQueueWatermarks {
+		check(watermark > 0, "RetryQueueWatermarks must be positive, got %d", watermark)
+	}
+	for _, ratio := range o.RetryQueueWatermarkRatios {
+		check(ratio > 0 && ratio <= 1, "RetryQueueWatermarkRatios must be between 0 and 1, got %g", ratio)
+	}
+
+	// Cross-field constraints
+	check(o.SampleKey == nil || o.SampleRate > 0, "SampleKey is set but SampleRate is 0, which drops every keyed log")
+	check(!(o.ConsoleOnly && o.Silent && len(o.Sinks) == 0), "ConsoleOnly and Silent without Sinks send logs nowhere")
+	check(o.SampleKey != nil || o.SampleRate == 0, "SampleRate is set but SampleKey is nil, so no log is sampled")
+	check(o.AutoRetry || o.RetryInterval == 0, "RetryInterval is set but AutoRetry is off, so nothing retries on that interval")
+	check(o.RetryQueue == nil || o.MaxRetryQueueSize == 0, "MaxRetryQueueSize bounds the built-in queue only, not a custom RetryQueue")
+	check(len(o.RetryQueueWatermarks) == 0 || o.MaxRetryQueueSize == 0, "RetryQueueWatermarks are ignored with MaxRetryQueueSize; use RetryQueueWatermarkRatios")
+	check(len(o.RetryQueueWatermarkRatios) == 0 || o.MaxRetryQueueSize > 0, "RetryQueueWatermarkRatios need MaxRetryQueueSize")
+
+	if len(problems) > 0 {
+		return &CheckLogsError{Type: "ConfigurationError", Message: "invalid options: " + strings.Join(problems, "; ")}
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes options from a config file, rejecting unknown
+// level names with the error of ParseLevel so a typo fails where the
+// config is loaded
+func (o *Options) UnmarshalJSON(b []byte) error {
+	type options Options
+	if err := json.Unmarshal(b, (*options)(o)); err != nil {
+		return err
+	}
+
+	var levels []LogLevel
+	for _, level := range []LogLevel{o.MinLevel, o.HeartbeatLevel} {
+		if level != "" {
+			levels = append(levels, level)
+		}
+	}
+	levels = append(levels, o.EnabledLevels...)
+	for level := range o.SampleRates {
+		levels = append(levels, level)
+	}
+	for level := range o.ConsoleFormatByLevel {
+		levels = append(levels, level)
+	}
+	for _, level := range levels {
+		if _, err := ParseLevel(string(level)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateURL checks that s is an absolute http or https URL
+func validateURL(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%q must use the http or https scheme", s)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("%q has no host", s)
+	}
+	return nil
+}
+
+// NewLoggerStrict creates a new CheckLogs logger after validating opts, so
+// misconfiguration is caught at startup rather than at the first send
+func NewLoggerStrict(apiKey string, opts *Options) (*Logger, error) {
+	if opts != nil {
+		if err := opts.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return NewLogger(apiKey, opts), nil
+}
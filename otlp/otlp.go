@@ -0,0 +1,316 @@
+// Package otlp exports CheckLogs entries to an OpenTelemetry collector using
+// the OTLP/HTTP protocol with JSON encoding
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	checklogs "github.com/checklogsdev/go-sdk"
+)
+
+const (
+	// DefaultEndpoint is the standard OTLP/HTTP logs endpoint of a local collector
+	DefaultEndpoint = "http://localhost:4318/v1/logs"
+	scopeName       = "github.com/checklogsdev/go-sdk"
+	// maxErrorBodyBytes caps how much of an error response is read
+	maxErrorBodyBytes = 64 * 1024
+)
+
+// OTLP severity numbers for each CheckLogs level
+var severityNumbers = map[checklogs.LogLevel]int{
+	checklogs.Debug:    5,
+	checklogs.Info:     9,
+	checklogs.Warning:  13,
+	checklogs.Error:    17,
+	checklogs.Critical: 21,
+}
+
+// Options represents configuration for the OTLP sink
+type Options struct {
+	Endpoint           string                 `json:"endpoint"`
+	Headers            map[string]string      `json:"headers"`
+	ResourceAttributes map[string]interface{} `json:"resource_attributes"`
+	BatchSize          int                    `json:"batch_size"`
+	Timeout            time.Duration          `json:"timeout"`
+	HTTPClient         *http.Client           `json:"-"`
+}
+
+// Sink converts log entries into OTLP LogRecords and exports them to a
+// collector. Add it to checklogs.Options.Sinks.
+type Sink struct {
+	options    Options
+	httpClient *http.Client
+	pending    []checklogs.LogData
+	mutex      sync.Mutex
+}
+
+// NewSink creates a new OTLP sink
+func NewSink(opts *Options) *Sink {
+	options := Options{
+		Endpoint:  DefaultEndpoint,
+		BatchSize: 1,
+		Timeout:   10 * time.Second,
+	}
+
+	if opts != nil {
+		if opts.Endpoint != "" {
+			options.Endpoint = opts.Endpoint
+		}
+		options.Headers = opts.Headers
+		options.ResourceAttributes = opts.ResourceAttributes
+		if opts.BatchSize > 0 {
+			options.BatchSize = opts.BatchSize
+		}
+		if opts.Timeout > 0 {
+			options.Timeout = opts.Timeout
+		}
+		options.HTTPClient = opts.HTTPClient
+	}
+
+	httpClient := options.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: options.Timeout}
+	}
+
+	return &Sink{
+		options:    options,
+		httpClient: httpClient,
+	}
+}
+
+// Write buffers a log entry and exports the buffer once it reaches BatchSize
+func (s *Sink) Write(ctx context.Context, data checklogs.LogData) error {
+	s.mutex.Lock()
+	s.pending = append(s.pending, data)
+	if len(s.pending) < s.options.BatchSize {
+		s.mutex.Unlock()
+		return nil
+	}
+	batch := s.pending
+	s.pending = nil
+	s.mutex.Unlock()
+
+	return s.export(ctx, batch)
+}
+
+// Flush exports any buffered log entries. The logger calls it on Shutdown
+// and Close.
+func (s *Sink) Flush(ctx context.Context) error {
+	s.mutex.Lock()
+	batch := s.pending
+	s.pending = nil
+	s.mutex.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+	return s.export(ctx, batch)
+}
+
+// export sends a batch of log entries to the collector
+func (s *Sink) export(ctx context.Context, batch []checklogs.LogData) error {
+	records := make([]logRecord, 0, len(batch))
+	for _, data := range batch {
+		records = append(records, toLogRecord(data))
+	}
+
+	payload := exportRequest{
+		ResourceLogs: []resourceLogs{{
+			Resource: resource{Attributes: toAttributes(s.options.ResourceAttributes)},
+			ScopeLogs: []scopeLogs{{
+				Scope:      scope{Name: scopeName, Version: checklogs.Version},
+				LogRecords: records,
+			}},
+		}},
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return &checklogs.CheckLogsError{Type: "SerializationError", Message: err.Error()}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.options.Endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return &checklogs.CheckLogsError{Type: "NetworkError", Message: err.Error(), Cause: err}
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "CheckLogs-Go-SDK/"+checklogs.Version)
+	for k, v := range s.options.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return &checklogs.CheckLogsError{Type: "NetworkError", Message: err.Error(), Cause: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		return &checklogs.CheckLogsError{
+			Type:    "APIError",
+			Message: fmt.Sprintf("OTLP export failed (HTTP %d): %s", resp.StatusCode, string(body)),
+			Code:    resp.StatusCode,
+		}
+	}
+
+	return nil
+}
+
+// toLogRecord maps a CheckLogs entry to an OTLP LogRecord
+func toLogRecord(data checklogs.LogData) logRecord {
+	attributes := map[string]interface{}{}
+	for k, v := range data.Context {
+		attributes[k] = v
+	}
+	if data.Source != "" {
+		attributes["source"] = data.Source
+	}
+	if data.UserID != nil {
+		attributes["user_id"] = *data.UserID
+	}
+	if data.Hostname != "" {
+		attributes["host.name"] = data.Hostname
+	}
+	if data.InstanceID != "" {
+		attributes["service.instance.id"] = data.InstanceID
+	}
+	if data.Detail != "" {
+		attributes["detail"] = data.Detail
+	}
+
+	timestamp := strconv.FormatInt(data.Timestamp.UnixNano(), 10)
+
+	return logRecord{
+		TimeUnixNano:         timestamp,
+		ObservedTimeUnixNano: strconv.FormatInt(time.Now().UnixNano(), 10),
+		SeverityNumber:       severityNumbers[data.Level],
+		SeverityText:         string(data.Level),
+		Body:                 anyValue{StringValue: &data.Message},
+		Attributes:           toAttributes(attributes),
+		TraceID:              data.TraceID,
+		SpanID:               data.SpanID,
+	}
+}
+
+// toAttributes converts a context map into OTLP key/value attributes
+func toAttributes(values map[string]interface{}) []keyValue {
+	if len(values) == 0 {
+		return nil
+	}
+	attributes := make([]keyValue, 0, len(values))
+	for k, v := range values {
+		attributes = append(attributes, keyValue{Key: k, Value: toAnyValue(v)})
+	}
+	return attributes
+}
+
+// toAnyValue converts a Go value into an OTLP AnyValue
+func toAnyValue(v interface{}) anyValue {
+	switch value := v.(type) {
+	case nil:
+		return anyValue{}
+	case string:
+		return anyValue{StringValue: &value}
+	case bool:
+		return anyValue{BoolValue: &value}
+	case int:
+		return intValue(int64(value))
+	case int32:
+		return intValue(int64(value))
+	case int64:
+		return intValue(value)
+	case uint:
+		return intValue(int64(value))
+	case uint32:
+		return intValue(int64(value))
+	case float32:
+		f := float64(value)
+		return anyValue{DoubleValue: &f}
+	case float64:
+		return anyValue{DoubleValue: &value}
+	case []interface{}:
+		values := make([]anyValue, 0, len(value))
+		for _, item := range value {
+			values = append(values, toAnyValue(item))
+		}
+		return anyValue{ArrayValue: &arrayValue{Values: values}}
+	case map[string]interface{}:
+		return anyValue{KvlistValue: &kvlistValue{Values: toAttributes(value)}}
+	default:
+		s := fmt.Sprintf("%v", value)
+		return anyValue{StringValue: &s}
+	}
+}
+
+func intValue(i int64) anyValue {
+	s := strconv.FormatInt(i, 10)
+	return anyValue{IntValue: &s}
+}
+
+// OTLP JSON payload types (ExportLogsServiceRequest)
+
+type exportRequest struct {
+	ResourceLogs []resourceLogs `json:"resourceLogs"`
+}
+
+type resourceLogs struct {
+	Resource  resource    `json:"resource"`
+	ScopeLogs []scopeLogs `json:"scopeLogs"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes,omitempty"`
+}
+
+type scopeLogs struct {
+	Scope      scope       `json:"scope"`
+	LogRecords []logRecord `json:"logRecords"`
+}
+
+type scope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type logRecord struct {
+	TimeUnixNano         string     `json:"timeUnixNano"`
+	ObservedTimeUnixNano string     `json:"observedTimeUnixNano"`
+	SeverityNumber       int        `json:"severityNumber,omitempty"`
+	SeverityText         string     `json:"severityText,omitempty"`
+	Body                 anyValue   `json:"body"`
+	Attributes           []keyValue `json:"attributes,omitempty"`
+	TraceID              string     `json:"traceId,omitempty"`
+	SpanID               string     `json:"spanId,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string      `json:"stringValue,omitempty"`
+	BoolValue   *bool        `json:"boolValue,omitempty"`
+	IntValue    *string      `json:"intValue,omitempty"`
+	DoubleValue *float64     `json:"doubleValue,omitempty"`
+	ArrayValue  *arrayValue  `json:"arrayValue,omitempty"`
+	KvlistValue *kvlistValue `json:"kvlistValue,omitempty"`
+}
+
+type arrayValue struct {
+	Values []anyValue `json:"values"`
+}
+
+type kvlistValue struct {
+	Values []keyValue `json:"values"`
+}
@@ -0,0 +1,80 @@
+package otlp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	checklogs "github.com/checklogsdev/go-sdk"
+)
+
+// collector is a fake OTLP collector counting the records it receives
+type collector struct {
+	*httptest.Server
+	mutex   sync.Mutex
+	records int
+}
+
+func newCollector(t *testing.T) *collector {
+	t.Helper()
+	c := &collector{}
+	c.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload exportRequest
+		json.NewDecoder(r.Body).Decode(&payload)
+		c.mutex.Lock()
+		for _, rl := range payload.ResourceLogs {
+			for _, sl := range rl.ScopeLogs {
+				c.records += len(sl.LogRecords)
+			}
+		}
+		c.mutex.Unlock()
+	}))
+	t.Cleanup(c.Close)
+	return c
+}
+
+func (c *collector) received() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.records
+}
+
+func TestLoggerShutdownFlushesPendingBatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		batchSize int
+		logs      int
+		close     func(ctx context.Context, l *checklogs.Logger) error
+	}{
+		{"Close with partial batch", 10, 3, func(ctx context.Context, l *checklogs.Logger) error { return l.Close(ctx) }},
+		{"Shutdown with partial batch", 10, 3, func(ctx context.Context, l *checklogs.Logger) error { return l.Shutdown(ctx) }},
+		{"Close after full batch", 2, 5, func(ctx context.Context, l *checklogs.Logger) error { return l.Close(ctx) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			collector := newCollector(t)
+			sink := NewSink(&Options{Endpoint: collector.URL, BatchSize: tt.batchSize})
+			logger := checklogs.NewLogger("", &checklogs.Options{
+				ConsoleOnly:   true,
+				ConsoleWriter: io.Discard,
+				Sinks:         []checklogs.Sink{sink},
+			})
+
+			for i := 0; i < tt.logs; i++ {
+				logger.Info(ctx, "exported")
+			}
+			if err := tt.close(ctx, logger); err != nil {
+				t.Fatalf("close: %v", err)
+			}
+			if got := collector.received(); got != tt.logs {
+				t.Errorf("collector received %d records, want %d", got, tt.logs)
+			}
+		})
+	}
+}
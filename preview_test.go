@@ -0,0 +1,64 @@
+package checklogs
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPreviewPayloadMatchesSentLog(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(*Options)
+		message   string
+		context   map[string]interface{}
+	}{
+		{"sequence", func(o *Options) { o.Sequence = true }, "ordered", nil},
+		{"redaction", nil, "login", map[string]interface{}{"password": "hunter2"}},
+		{"truncation", func(o *Options) { o.TruncateOversized = true }, strings.Repeat("x", DefaultMaxMessageLength+1), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			logger := newTestLogger(t, server, tt.configure)
+
+			// Previews repeated before a send do not change what is sent
+			var preview LogData
+			for i := 0; i < 2; i++ {
+				payload, err := logger.PreviewPayload(Info, tt.message, tt.context)
+				if err != nil {
+					t.Fatalf("PreviewPayload: %v", err)
+				}
+				preview = LogData{}
+				if err := json.Unmarshal(payload, &preview); err != nil {
+					t.Fatalf("decoding preview: %v", err)
+				}
+			}
+			if err := logger.Info(context.Background(), tt.message, tt.context); err != nil {
+				t.Fatalf("Info: %v", err)
+			}
+
+			logs := server.logs()
+			if len(logs) != 1 {
+				t.Fatalf("server received %d logs, want 1", len(logs))
+			}
+			sent := logs[0]
+			if preview.Seq != sent.Seq {
+				t.Errorf("preview seq = %d, sent seq = %d", preview.Seq, sent.Seq)
+			}
+			if preview.Message != sent.Message {
+				t.Errorf("preview message length = %d, sent %d", len(preview.Message), len(sent.Message))
+			}
+			if len(preview.Context) != len(sent.Context) {
+				t.Errorf("preview context = %v, sent %v", preview.Context, sent.Context)
+			}
+			for k, v := range sent.Context {
+				if preview.Context[k] != v {
+					t.Errorf("preview %s = %v, sent %v", k, preview.Context[k], v)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,438 @@
+package checklogs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrLogNotFound is returned when a requested log does not exist
+var ErrLogNotFound = &CheckLogsError{Type: "NotFoundError", Message: "log not found", Code: 404}
+
+// DefaultPageSize is the number of logs requested per page by IterLogs
+const DefaultPageSize = 100
+
+// maxPageAttempts bounds the attempts made to fetch one page of logs
+const maxPageAttempts = 3
+
+// GetLogsParams filters the logs returned by IterLogs. Zero values are not
+// sent to the API. Query is a full-text search: the server matches logs
+// whose message or context values contain every word of the query,
+// case-insensitively and regardless of order; quote a phrase to match it
+// as a substring.
+type GetLogsParams struct {
+	Level      LogLevel  `json:"level,omitempty"`
+	Source     string    `json:"source,omitempty"`
+	UserID     *int64    `json:"user_id,omitempty"`
+	TraceID    string    `json:"trace_id,omitempty"`
+	InstanceID string    `json:"instance_id,omitempty"`
+	Query      string    `json:"q,omitempty"`
+	Since      time.Time `json:"since,omitempty"`
+	Until      time.Time `json:"until,omitempty"`
+	PageSize   int       `json:"page_size,omitempty"`
+}
+
+// query encodes the params as URL query parameters
+func (p GetLogsParams) query() url.Values {
+	query := url.Values{}
+	if p.Level != "" {
+		query.Set("level", string(p.Level))
+	}
+	if p.Source != "" {
+		query.Set("source", p.Source)
+	}
+	if p.UserID != nil {
+		query.Set("user_id", strconv.FormatInt(*p.UserID, 10))
+	}
+	if p.TraceID != "" {
+		query.Set("trace_id", p.TraceID)
+	}
+	if p.InstanceID != "" {
+		query.Set("instance_id", p.InstanceID)
+	}
+	if p.Query != "" {
+		query.Set("q", p.Query)
+	}
+	if !p.Since.IsZero() {
+		query.Set("since", p.Since.UTC().Format(time.RFC3339Nano))
+	}
+	if !p.Until.IsZero() {
+		query.Set("until", p.Until.UTC().Format(time.RFC3339Nano))
+	}
+	pageSize := p.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	query.Set("limit", strconv.Itoa(pageSize))
+	return query
+}
+
+// logsPage is one page of results from GET /api/logs
+type logsPage struct {
+	Logs       []LogData `json:"logs"`
+	NextCursor string    `json:"next_cursor"`
+}
+
+// LogIterator streams logs matching a query, fetching one page at a time.
+// Iterate with Next and Value, then check Err:
+//
+//	it := logger.IterLogs(ctx, checklogs.GetLogsParams{Level: checklogs.Error})
+//	for it.Next() {
+//		fmt.Println(it.Value().Message)
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+type LogIterator struct {
+	logger  *Logger
+	ctx     context.Context
+	query   url.Values
+	page    []LogData
+	index   int
+	cursor  string
+	started bool
+	current LogData
+	err     error
+}
+
+// IterLogs returns an iterator over the logs matching params. Pages are
+// fetched lazily as the iterator advances, so only one page is held in
+// memory at a time; transient failures are retried before Err reports them.
+func (l *Logger) IterLogs(ctx context.Context, params GetLogsParams) *LogIterator {
+	return &LogIterator{
+		logger: l,
+		ctx:    ctx,
+		query:  params.query(),
+	}
+}
+
+// Next advances to the next log, fetching the next page when needed. It
+// returns false when the results are exhausted or an error occurred.
+func (it *LogIterator) Next() bool {
+	for it.index >= len(it.page) {
+		if it.err != nil || (it.started && it.cursor == "") {
+			return false
+		}
+		if err := it.fetch(); err != nil {
+			it.err = err
+			return false
+		}
+	}
+
+	it.current = it.page[it.index]
+	it.index++
+	return true
+}
+
+// Value returns the log at the current position
+func (it *LogIterator) Value() LogData {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *LogIterator) Err() error {
+	return it.err
+}
+
+// fetch loads the next page, retrying transient failures
+func (it *LogIterator) fetch() error {
+	query := url.Values{}
+	for k, v := range it.query {
+		query[k] = v
+	}
+	if it.cursor != "" {
+		query.Set("cursor", it.cursor)
+	}
+
+	var page logsPage
+	var err error
+	for attempt := 0; attempt < maxPageAttempts; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(time.Duration(attempt) * 500 * time.Millisecond)
+			select {
+			case <-it.ctx.Done():
+				timer.Stop()
+				return &CheckLogsError{Type: "NetworkError", Message: it.ctx.Err().Error(), Cause: it.ctx.Err()}
+			case <-timer.C:
+			}
+		}
+
+		page = logsPage{}
+		err = it.logger.requestJSON(it.ctx, "GET", "/api/logs", query, &page)
+		if err == nil || !isTransientError(err) {
+			break
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	it.started = true
+	it.page = page.Logs
+	it.index = 0
+	it.cursor = page.NextCursor
+	return nil
+}
+
+// isTransientError reports whether a failed request is worth retrying
+func isTransientError(err error) bool {
+	e, ok := err.(*CheckLogsError)
+	if !ok {
+		return false
+	}
+	return e.Type == "NetworkError" || e.Code == 429 || e.Code >= 500
+}
+
+// GetLog fetches a single log by ID, including server-side metadata such as
+// the ID and the time it was received
+func (l *Logger) GetLog(ctx context.Context, id string) (*LogData, error) {
+	if id == "" {
+		return nil, &CheckLogsError{Type: "ValidationError", Message: "log ID is required"}
+	}
+
+	var data LogData
+	if err := l.requestJSON(ctx, "GET", "/api/logs/"+url.PathEscape(id), nil, &data); err != nil {
+		if e, ok := err.(*CheckLogsError); ok && e.Code == 404 {
+			return nil, ErrLogNotFound
+		}
+		return nil, err
+	}
+	return &data, nil
+}
+
+// requestJSON performs an authenticated request against the API and decodes
+// the JSON response into out
+func (l *Logger) requestJSON(ctx context.Context, method, path string, query url.Values, out interface{}) error {
+	if l.apiKey == "" {
+		return &CheckLogsError{Type: "ConfigurationError", Message: "API key is required"}
+	}
+
+	baseURL, err := l.baseURL(ctx)
+	if err != nil {
+		return err
+	}
+
+	endpoint := baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, nil)
+	if err != nil {
+		return &CheckLogsError{Type: "NetworkError", Message: err.Error(), Cause: err}
+	}
+
+	req.Header.Set("Authorization", "Bearer "+l.apiKey)
+	req.Header.Set("User-Agent", "CheckLogs-Go-SDK/"+Version)
+
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return &CheckLogsError{Type: "NetworkError", Message: err.Error(), Cause: err}
+	}
+	defer resp.Body.Close()
+	l.limitResponse(resp)
+
+	switch {
+	case resp.StatusCode == 404:
+		return &CheckLogsError{Type: "NotFoundError", Message: "resource not found", Code: 404}
+	case resp.StatusCode == 401:
+		return &CheckLogsError{Type: "AuthenticationError", Message: "Invalid API key", Code: 401}
+	case resp.StatusCode == 403:
+		return &CheckLogsError{Type: "AuthorizationError", Message: "API key does not have required permissions", Code: 403}
+	case resp.StatusCode >= 400:
+		body, _ := io.ReadAll(resp.Body)
+		return &CheckLogsError{Type: "APIError", Message: fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body)), Code: resp.StatusCode}
+	}
+
+	return l.decodeResponse(resp, out)
+}
+
+// AggregateDimension is a dimension logs can be grouped by in Aggregate
+type AggregateDimension string
+
+const (
+	GroupByLevel  AggregateDimension = "level"
+	GroupBySource AggregateDimension = "source"
+	GroupByHour   AggregateDimension = "hour"
+)
+
+// AggregateRequest describes a server-side aggregation over a time range.
+// Filters narrow the logs counted, as they do for IterLogs.
+type AggregateRequest struct {
+	GroupBy AggregateDimension `json:"group_by"`
+	Since   time.Time          `json:"since"`
+	Until   time.Time          `json:"until"`
+	Level   LogLevel           `json:"level,omitempty"`
+	Source  string             `json:"source,omitempty"`
+}
+
+// AggregateBucket holds the number of logs for one value of the dimension.
+// For GroupByHour the key is the start of the hour in RFC 3339 format.
+type AggregateBucket struct {
+	Key   string `json:"key"`
+	Count int64  `json:"count"`
+}
+
+// AggregateResponse is the result of an aggregation query
+type AggregateResponse struct {
+	GroupBy AggregateDimension `json:"group_by"`
+	Buckets []AggregateBucket  `json:"buckets"`
+	Total   int64              `json:"total"`
+}
+
+// Aggregate counts logs grouped by a dimension over a time range
+func (l *Logger) Aggregate(ctx context.Context, req AggregateRequest) (*AggregateResponse, error) {
+	switch req.GroupBy {
+	case GroupByLevel, GroupBySource, GroupByHour:
+	default:
+		return nil, &CheckLogsError{Type: "ValidationError", Message: fmt.Sprintf("invalid group by dimension: %q", req.GroupBy)}
+	}
+	if req.Since.IsZero() || req.Until.IsZero() {
+		return nil, &CheckLogsError{Type: "ValidationError", Message: "time range is required"}
+	}
+	if !req.Until.After(req.Since) {
+		return nil, &CheckLogsError{Type: "ValidationError", Message: "until must be after since"}
+	}
+
+	query := url.Values{}
+	query.Set("group_by", string(req.GroupBy))
+	query.Set("since", req.Since.UTC().Format(time.RFC3339Nano))
+	query.Set("until", req.Until.UTC().Format(time.RFC3339Nano))
+	if req.Level != "" {
+		query.Set("level", string(req.Level))
+	}
+	if req.Source != "" {
+		query.Set("source", req.Source)
+	}
+
+	var resp AggregateResponse
+	if err := l.requestJSON(ctx, "GET", "/api/aggregate", query, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteLogsParams selects the logs removed by DeleteLogs. At least one
+// filter must be set.
+type DeleteLogsParams struct {
+	Level  LogLevel  `json:"level,omitempty"`
+	Source string    `json:"source,omitempty"`
+	Since  time.Time `json:"since,omitempty"`
+	Until  time.Time `json:"until,omitempty"`
+}
+
+// DeleteLogs permanently deletes the logs matching params and returns the
+// number removed
+func (l *Logger) DeleteLogs(ctx context.Context, params DeleteLogsParams) (int, error) {
+	query := url.Values{}
+	if params.Level != "" {
+		query.Set("level", string(params.Level))
+	}
+	if params.Source != "" {
+		query.Set("source", params.Source)
+	}
+	if !params.Since.IsZero() {
+		query.Set("since", params.Since.UTC().Format(time.RFC3339Nano))
+	}
+	if !params.Until.IsZero() {
+		query.Set("until", params.Until.UTC().Format(time.RFC3339Nano))
+	}
+	if len(query) == 0 {
+		return 0, &CheckLogsError{Type: "ValidationError", Message: "at least one filter is required to delete logs"}
+	}
+
+	var resp struct {
+		Deleted int `json:"deleted"`
+	}
+	if err := l.requestJSON(ctx, "DELETE", "/api/logs", query, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Deleted, nil
+}
+
+// PruneOlderThan deletes every log older than age and returns the number
+// removed, for client-managed retention jobs
+func (l *Logger) PruneOlderThan(ctx context.Context, age time.Duration) (int, error) {
+	if age <= 0 {
+		return 0, &CheckLogsError{Type: "ValidationError", Message: "age must be positive"}
+	}
+	return l.DeleteLogs(ctx, DeleteLogsParams{Until: time.Now().Add(-age)})
+}
+
+// maxParallelFetches bounds the shards GetLogsParallel fetches at once
+const maxParallelFetches = 8
+
+// GetLogsParallel fetches all logs matching params by splitting the time
+// range between Since and Until into shards fetched concurrently, at most 8
+// at a time, and returns them merged in timestamp order. Both Since and Until
+// are required. The first error cancels the remaining fetches.
+func (l *Logger) GetLogsParallel(ctx context.Context, params GetLogsParams, shards int) ([]LogData, error) {
+	if params.Since.IsZero() || params.Until.IsZero() || !params.Until.After(params.Since) {
+		return nil, &CheckLogsError{Type: "ValidationError", Message: "a time range with since before until is required"}
+	}
+	if shards < 1 {
+		shards = 1
+	}
+
+	fetchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	step := params.Until.Sub(params.Since) / time.Duration(shards)
+	results := make([][]LogData, shards)
+	errs := make(chan error, shards)
+	semaphore := make(chan struct{}, maxParallelFetches)
+	var wg sync.WaitGroup
+
+	for i := 0; i < shards; i++ {
+		shard := params
+		shard.Since = params.Since.Add(time.Duration(i) * step)
+		if i < shards-1 {
+			shard.Until = shard.Since.Add(step)
+		}
+
+		wg.Add(1)
+		go func(i int, shard GetLogsParams) {
+			defer wg.Done()
+
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-fetchCtx.Done():
+				return
+			}
+
+			it := l.IterLogs(fetchCtx, shard)
+			for it.Next() {
+				results[i] = append(results[i], it.Value())
+			}
+			if err := it.Err(); err != nil {
+				errs <- err
+				cancel()
+			}
+		}(i, shard)
+	}
+	wg.Wait()
+	close(errs)
+
+	if err := <-errs; err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, &CheckLogsError{Type: "NetworkError", Message: err.Error(), Cause: err}
+	}
+
+	var merged []LogData
+	for _, logs := range results {
+		merged = append(merged, logs...)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Timestamp.Before(merged[j].Timestamp)
+	})
+	return merged, nil
+}
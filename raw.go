@@ -0,0 +1,97 @@
+package checklogs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// Field names recognized by LogRaw, covering the common JSON loggers (zap,
+// logrus, slog, zerolog, pino, bunyan, Logstash)
+var (
+	rawMessageKeys    = []string{"message", "msg", "@message", "M"}
+	rawLevelKeys      = []string{"level", "severity", "lvl", "levelname", "L"}
+	rawTimeKeys       = []string{"timestamp", "time", "ts", "@timestamp", "T"}
+	rawStacktraceKeys = []string{"stacktrace", "stack", "stack_trace", "S"}
+)
+
+// LogRaw sends a log line already encoded as a JSON object by another
+// logger. The message, level and timestamp are read from their usual field
+// names (msg, severity, ts and so on), a stack trace becomes the detail and
+// every other field is folded into the context. Input that is not a single
+// JSON object returns a ValidationError.
+func (l *Logger) LogRaw(ctx context.Context, jsonLine []byte) error {
+	data, err := l.parseRawEntry(ctx, jsonLine)
+	if err != nil {
+		return err
+	}
+	_, err = l.sendLog(ctx, data)
+	return err
+}
+
+// parseRawEntry converts a JSON log line into a log entry
+func (l *Logger) parseRawEntry(ctx context.Context, line []byte) (LogData, error) {
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+
+	var fields map[string]interface{}
+	if err := decoder.Decode(&fields); err != nil {
+		return LogData{}, &CheckLogsError{Type: "ValidationError", Message: "invalid JSON log: " + err.Error()}
+	}
+	if fields == nil {
+		return LogData{}, &CheckLogsError{Type: "ValidationError", Message: "invalid JSON log: not an object"}
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return LogData{}, &CheckLogsError{Type: "ValidationError", Message: "invalid JSON log: unexpected data after object"}
+	}
+
+	message, _ := takeField(fields, rawMessageKeys).(string)
+	level := rawLevel(takeField(fields, rawLevelKeys))
+	timestamp := parseZapTime(takeField(fields, rawTimeKeys))
+	stacktrace, _ := takeField(fields, rawStacktraceKeys).(string)
+
+	data := l.buildLogData(ctx, level, message, fields)
+	data.Detail = stacktrace
+	if !timestamp.IsZero() {
+		data.Timestamp = timestamp
+	}
+	return data, nil
+}
+
+// rawLevel maps a level name, or a numeric pino/bunyan level, to a LogLevel.
+// Unrecognized levels are sent as Info.
+func rawLevel(v interface{}) LogLevel {
+	switch value := v.(type) {
+	case json.Number:
+		n, err := value.Int64()
+		if err != nil {
+			return Info
+		}
+		switch {
+		case n <= 20:
+			return Debug
+		case n <= 30:
+			return Info
+		case n <= 40:
+			return Warning
+		case n <= 50:
+			return Error
+		default:
+			return Critical
+		}
+	case string:
+		switch strings.ToLower(value) {
+		case "trace", "debug":
+			return Debug
+		case "warn", "warning":
+			return Warning
+		case "error", "err":
+			return Error
+		case "critical", "crit", "fatal", "panic", "dpanic", "alert", "emerg", "emergency":
+			return Critical
+		}
+	}
+	return Info
+}
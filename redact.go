@@ -0,0 +1,62 @@
+package checklogs
+
+import "strings"
+
+// RedactedValue replaces the value of a redacted context key
+const RedactedValue = "[REDACTED]"
+
+// DefaultRedactKeys are the context keys always redacted, matched
+// case-insensitively
+var DefaultRedactKeys = []string{
+	"password",
+	"passwd",
+	"secret",
+	"token",
+	"access_token",
+	"refresh_token",
+	"api_key",
+	"apikey",
+	"authorization",
+	"cookie",
+	"set-cookie",
+	"private_key",
+}
+
+// newRedactSet builds the lowercased set of keys to redact: the defaults
+// plus extra
+func newRedactSet(extra []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(DefaultRedactKeys)+len(extra))
+	for _, key := range DefaultRedactKeys {
+		set[strings.ToLower(key)] = struct{}{}
+	}
+	for _, key := range extra {
+		set[strings.ToLower(key)] = struct{}{}
+	}
+	return set
+}
+
+// redactContext replaces the values of sensitive keys in a log's own
+// context map, recursing into nested maps
+func (l *Logger) redactContext(context map[string]interface{}) {
+	for k, v := range context {
+		context[k] = l.redactValue(k, v)
+	}
+}
+
+// redactValue returns RedactedValue for a sensitive key, or the value with
+// its nested maps redacted. Nested maps are copied so the caller's maps are
+// never modified.
+func (l *Logger) redactValue(key string, value interface{}) interface{} {
+	if _, ok := l.redactKeys[strings.ToLower(key)]; ok {
+		return RedactedValue
+	}
+	nested, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	redacted := make(map[string]interface{}, len(nested))
+	for k, v := range nested {
+		redacted[k] = l.redactValue(k, v)
+	}
+	return redacted
+}
@@ -0,0 +1,59 @@
+package checklogs
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRedactionAndAllowlistOnEverySendPath(t *testing.T) {
+	tests := []struct {
+		name string
+		send func(ctx context.Context, l *Logger, logContext map[string]interface{}) error
+	}{
+		{"Info", func(ctx context.Context, l *Logger, logContext map[string]interface{}) error {
+			return l.Info(ctx, "login", logContext)
+		}},
+		{"LogBatch", func(ctx context.Context, l *Logger, logContext map[string]interface{}) error {
+			return l.LogBatch(ctx, []LogData{{Message: "login", Level: Info, Context: logContext}})
+		}},
+		{"LogConfirmed", func(ctx context.Context, l *Logger, logContext map[string]interface{}) error {
+			_, err := l.LogConfirmed(ctx, LogData{Message: "login", Level: Info, Context: logContext})
+			return err
+		}},
+		{"LogRaw", func(ctx context.Context, l *Logger, logContext map[string]interface{}) error {
+			return l.LogRaw(ctx, []byte(`{"msg":"login","level":"info","user":"ada","password":"hunter2","internal":"x"}`))
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			logger := newTestLogger(t, server, func(o *Options) {
+				o.AllowedContextKeys = []string{"user", "password"}
+			})
+			logContext := map[string]interface{}{"user": "ada", "password": "hunter2", "internal": "x"}
+
+			if err := tt.send(context.Background(), logger, logContext); err != nil {
+				t.Fatalf("send: %v", err)
+			}
+
+			logs := server.logs()
+			if len(logs) != 1 {
+				t.Fatalf("server received %d logs, want 1", len(logs))
+			}
+			got := logs[0].Context
+			if got["password"] != RedactedValue {
+				t.Errorf("password = %v, want %q", got["password"], RedactedValue)
+			}
+			if got["user"] != "ada" {
+				t.Errorf("user = %v, want ada", got["user"])
+			}
+			if _, ok := got["internal"]; ok {
+				t.Errorf("internal key outside the allowlist was sent: %v", got)
+			}
+			if logContext["password"] != "hunter2" {
+				t.Errorf("caller's context was modified: %v", logContext)
+			}
+		})
+	}
+}
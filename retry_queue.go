@@ -0,0 +1,97 @@
+package checklogs
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// DropPolicy decides which log is lost when a bounded retry queue is full
+type DropPolicy int
+
+const (
+	// DropOldest evicts the oldest queued log to make room for the new one
+	DropOldest DropPolicy = iota
+	// DropNewest rejects the new log, which is reported as Dropped
+	DropNewest
+)
+
+// ErrRetryQueueFull is returned by the in-memory retry queue when it is full
+// and the DropNewest policy rejects a log
+var ErrRetryQueueFull = &CheckLogsError{Type: "RetryQueueFullError", Message: "retry queue is full"}
+
+// RetryQueueStatus describes the retry queue and the logs it has lost
+type RetryQueueStatus struct {
+	Size    int   `json:"size"`
+	MaxSize int   `json:"max_size"`
+	Dropped int64 `json:"dropped"`
+}
+
+// RetryQueue stores logs that failed to send until they are flushed. The
+// default is an in-memory queue; set Options.RetryQueue to plug in a durable
+// or shared store such as Redis or SQLite.
+//
+// Implementations must be safe for concurrent use: Add is called from every
+// goroutine that logs, while Drain, Len and Clear may run at the same time
+// from flushes or monitoring. Drain must atomically remove and return the
+// stored entries so an entry is never handed out twice, and entries should
+// come back in the order they were added. An error from Add means the log
+// could not be queued and is reported to the caller as Dropped.
+type RetryQueue interface {
+	Add(data LogData) error
+	Drain() []LogData
+	Len() int
+	Clear()
+}
+
+// memoryRetryQueue is the default in-memory RetryQueue, optionally bounded
+// to maxSize entries
+type memoryRetryQueue struct {
+	mutex   sync.RWMutex
+	entries []LogData
+	maxSize int
+	policy  DropPolicy
+	evicted atomic.Int64
+}
+
+// newMemoryRetryQueue creates an empty in-memory retry queue. A maxSize of
+// 0 leaves it unbounded.
+func newMemoryRetryQueue(maxSize int, policy DropPolicy) *memoryRetryQueue {
+	return &memoryRetryQueue{entries: make([]LogData, 0), maxSize: maxSize, policy: policy}
+}
+
+func (q *memoryRetryQueue) Add(data LogData) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if q.maxSize > 0 && len(q.entries) >= q.maxSize {
+		if q.policy == DropNewest {
+			return ErrRetryQueueFull
+		}
+		q.entries[0] = LogData{} // Release the evicted entry
+		q.entries = q.entries[1:]
+		q.evicted.Add(1)
+	}
+	q.entries = append(q.entries, data)
+	return nil
+}
+
+func (q *memoryRetryQueue) Drain() []LogData {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	// Hand the entries over and start a new slice, rather than reslicing,
+	// so a spike does not pin a large backing array
+	queue := q.entries
+	q.entries = make([]LogData, 0)
+	return queue
+}
+
+func (q *memoryRetryQueue) Len() int {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+	return len(q.entries)
+}
+
+func (q *memoryRetryQueue) Clear() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.entries = make([]LogData, 0) // Release the backing array
+}
@@ -0,0 +1,255 @@
+package checklogs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRetryQueueDropPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        DropPolicy
+		maxSize       int
+		logs          int
+		wantDelivered []string
+		wantDropped   int64
+		// wantRejected is the number of last logs reported Dropped
+		wantRejected int
+	}{
+		{
+			name:          "unbounded keeps everything",
+			logs:          5,
+			wantDelivered: []string{"log 1", "log 2", "log 3", "log 4", "log 5"},
+		},
+		{
+			name:          "under capacity drops nothing",
+			policy:        DropNewest,
+			maxSize:       5,
+			logs:          3,
+			wantDelivered: []string{"log 1", "log 2", "log 3"},
+		},
+		{
+			name:          "drop oldest evicts the first logs",
+			policy:        DropOldest,
+			maxSize:       3,
+			logs:          5,
+			wantDelivered: []string{"log 3", "log 4", "log 5"},
+			wantDropped:   2,
+		},
+		{
+			name:          "drop newest rejects the last logs",
+			policy:        DropNewest,
+			maxSize:       3,
+			logs:          5,
+			wantDelivered: []string{"log 1", "log 2", "log 3"},
+			wantDropped:   2,
+			wantRejected:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			server := newTestServer(t)
+			logger := newTestLogger(t, server, func(o *Options) {
+				o.MaxRetryQueueSize = tt.maxSize
+				o.DropPolicy = tt.policy
+			})
+
+			server.setStatus(http.StatusServiceUnavailable)
+			for i := 1; i <= tt.logs; i++ {
+				outcome, _ := logger.LogResult(ctx, Error, fmt.Sprintf("log %d", i))
+				wantOutcome := Queued
+				if i > tt.logs-tt.wantRejected {
+					wantOutcome = Dropped
+				}
+				if outcome != wantOutcome {
+					t.Errorf("log %d outcome = %v, want %v", i, outcome, wantOutcome)
+				}
+			}
+
+			status := logger.RetryQueueStatus()
+			if status.Size != len(tt.wantDelivered) {
+				t.Errorf("Size = %d, want %d", status.Size, len(tt.wantDelivered))
+			}
+			if status.MaxSize != tt.maxSize {
+				t.Errorf("MaxSize = %d, want %d", status.MaxSize, tt.maxSize)
+			}
+			if status.Dropped != tt.wantDropped {
+				t.Errorf("Dropped = %d, want %d", status.Dropped, tt.wantDropped)
+			}
+
+			server.setStatus(http.StatusOK)
+			logger.FlushRetryQueue(ctx)
+			var delivered []string
+			for _, data := range server.logs() {
+				delivered = append(delivered, data.Message)
+			}
+			if !reflect.DeepEqual(delivered, tt.wantDelivered) {
+				t.Errorf("delivered %v, want %v", delivered, tt.wantDelivered)
+			}
+		})
+	}
+}
+
+func TestMemoryRetryQueueAddWhenFull(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  DropPolicy
+		wantErr error
+		want    []string
+	}{
+		{"drop oldest", DropOldest, nil, []string{"b", "c"}},
+		{"drop newest", DropNewest, ErrRetryQueueFull, []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newMemoryRetryQueue(2, tt.policy)
+			q.Add(LogData{Message: "a"})
+			q.Add(LogData{Message: "b"})
+
+			if err := q.Add(LogData{Message: "c"}); !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("Add when full = %v, want %v", err, tt.wantErr)
+			}
+
+			var got []string
+			for _, data := range q.Drain() {
+				got = append(got, data.Message)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("queue = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryRetryQueueReleasesBackingArray(t *testing.T) {
+	tests := []struct {
+		name  string
+		empty func(q *memoryRetryQueue)
+	}{
+		{"Clear", func(q *memoryRetryQueue) { q.Clear() }},
+		{"Drain", func(q *memoryRetryQueue) { q.Drain() }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newMemoryRetryQueue(0, DropOldest)
+			for i := 0; i < 10000; i++ {
+				q.Add(LogData{Message: "spike"})
+			}
+
+			tt.empty(q)
+			if got := cap(q.entries); got != 0 {
+				t.Errorf("capacity after %s = %d, want 0", tt.name, got)
+			}
+			q.Add(LogData{Message: "after"})
+			if got := q.Len(); got != 1 {
+				t.Errorf("Len after %s and Add = %d, want 1", tt.name, got)
+			}
+		})
+	}
+}
+
+func TestClearAndFlushRetryQueue(t *testing.T) {
+	tests := []struct {
+		name          string
+		queued        int
+		clear         bool
+		flushStatus   int
+		wantFlushed   int
+		wantRemaining int
+		wantDelivered int
+	}{
+		{"flush delivers every log", 3, false, http.StatusOK, 3, 0, 3},
+		{"flush during outage requeues", 3, false, http.StatusServiceUnavailable, 0, 3, 0},
+		{"flush after permanent failure drops", 3, false, http.StatusBadRequest, 0, 0, 0},
+		{"clear discards every log", 3, true, http.StatusOK, 0, 0, 0},
+		{"flush of an empty queue", 0, false, http.StatusOK, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			server := newTestServer(t)
+			logger := newTestLogger(t, server, nil)
+
+			server.setStatus(http.StatusServiceUnavailable)
+			for i := 0; i < tt.queued; i++ {
+				logger.Error(ctx, "outage")
+			}
+			if got := logger.GetRetryQueueSize(); got != tt.queued {
+				t.Fatalf("queued %d logs, want %d", got, tt.queued)
+			}
+
+			if tt.clear {
+				logger.ClearRetryQueue()
+			}
+			server.setStatus(tt.flushStatus)
+			if got := logger.FlushRetryQueue(ctx); got != tt.wantFlushed {
+				t.Errorf("FlushRetryQueue = %d, want %d", got, tt.wantFlushed)
+			}
+			if got := logger.GetRetryQueueSize(); got != tt.wantRemaining {
+				t.Errorf("retry queue size = %d, want %d", got, tt.wantRemaining)
+			}
+			if got := len(server.logs()); got != tt.wantDelivered {
+				t.Errorf("server received %d logs, want %d", got, tt.wantDelivered)
+			}
+		})
+	}
+}
+
+func TestBackoffFunc(t *testing.T) {
+	tests := []struct {
+		name    string
+		backoff func(attempt int) time.Duration
+		attempt int
+		want    time.Duration
+	}{
+		{"fixed delay", func(int) time.Duration { return 250 * time.Millisecond }, 3, 250 * time.Millisecond},
+		{"by attempt", func(attempt int) time.Duration { return time.Duration(attempt) * time.Second }, 2, 2 * time.Second},
+		{"negative clamped to zero", func(int) time.Duration { return -time.Second }, 1, 0},
+		{"zero", func(int) time.Duration { return 0 }, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := NewLogger("", &Options{BackoffFunc: tt.backoff, ConsoleWriter: io.Discard})
+			if got := logger.retryDelay(tt.attempt, time.Second); got != tt.want {
+				t.Errorf("retryDelay(%d) = %v, want %v", tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoffFuncDrivesInProcessRetries(t *testing.T) {
+	server := newTestServer(t)
+	var attempts []int
+	logger := newTestLogger(t, server, func(o *Options) {
+		o.MaxRetries = 3
+		o.BackoffFunc = func(attempt int) time.Duration {
+			attempts = append(attempts, attempt)
+			return -time.Hour
+		}
+	})
+
+	server.setStatus(http.StatusServiceUnavailable)
+	start := time.Now()
+	outcome, _ := logger.LogResult(context.Background(), Error, "retried")
+	if outcome != Queued {
+		t.Errorf("outcome = %v, want Queued", outcome)
+	}
+	if !reflect.DeepEqual(attempts, []int{1, 2, 3}) {
+		t.Errorf("BackoffFunc attempts = %v, want [1 2 3]", attempts)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("retries took %v despite zero delays", elapsed)
+	}
+}
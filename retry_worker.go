@@ -0,0 +1,118 @@
+package checklogs
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// DefaultRetryInterval is the default interval of the AutoRetry worker
+const DefaultRetryInterval = 5 * time.Second
+
+// DefaultRetryBaseDelay is the default delay before the first in-process
+// retry of a failed send
+const DefaultRetryBaseDelay = 100 * time.Millisecond
+
+// maxRetryBackoff caps the delay between two attempts of a queued log
+const maxRetryBackoff = 30 * time.Second
+
+// exponentialBackoff returns the delay before the given attempt, doubling
+// from base and capped at 30s
+func exponentialBackoff(attempt int, base time.Duration) time.Duration {
+	if attempt <= 1 {
+		return base
+	}
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= maxRetryBackoff {
+			return maxRetryBackoff
+		}
+	}
+	return delay
+}
+
+// withJitter spreads a delay randomly over its upper half, so goroutines
+// failing together do not retry in lockstep
+func withJitter(delay time.Duration) time.Duration {
+	if delay <= 1 {
+		return delay
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)))
+}
+
+// retryDelay returns the delay before the given retry attempt: the result
+// of Options.BackoffFunc, clamped to zero, or an exponential backoff from
+// base with jitter
+func (l *Logger) retryDelay(attempt int, base time.Duration) time.Duration {
+	if l.options.BackoffFunc == nil {
+		return withJitter(exponentialBackoff(attempt, base))
+	}
+	if delay := l.options.BackoffFunc(attempt); delay > 0 {
+		return delay
+	}
+	return 0
+}
+
+// retryWorker periodically retries the logs in the retry queue
+type retryWorker struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// startRetryWorker starts retrying queued logs every RetryInterval. Each log
+// waits an exponentially growing delay after each failed attempt.
+func (l *Logger) startRetryWorker() *retryWorker {
+	interval := l.options.RetryInterval
+	if interval <= 0 {
+		interval = DefaultRetryInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &retryWorker{cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(w.done)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(l.startupJitter()):
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				l.retryDue(ctx, interval)
+			}
+		}
+	}()
+	return w
+}
+
+// stop stops the worker, aborting any request in flight, and waits for it
+// to exit
+func (w *retryWorker) stop() {
+	w.cancel()
+	<-w.done
+}
+
+// retryDue sends the queued logs whose backoff has elapsed and keeps the
+// others queued
+func (l *Logger) retryDue(ctx context.Context, base time.Duration) {
+	now := time.Now()
+	for _, data := range l.retryQueue.Drain() {
+		if ctx.Err() != nil || now.Before(data.nextRetry) {
+			l.retryQueue.Add(data)
+			continue
+		}
+		data.attempts++
+		data.nextRetry = now.Add(l.retryDelay(data.attempts, base))
+		l.sendLog(ctx, data, replay())
+	}
+	l.rearmRetryQueueWatermarks(l.retryQueue.Len())
+}
@@ -0,0 +1,139 @@
+package checklogs
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// DefaultAdaptiveSamplingInterval is how often adaptive sampling adjusts rates
+const DefaultAdaptiveSamplingInterval = 10 * time.Second
+
+// AdaptiveSampling scales the sample rate of some levels with the error rate
+// observed by the logger: Floor while sends succeed, rising linearly to
+// Ceiling as the error rate over the last interval reaches
+// ErrorRateThreshold
+type AdaptiveSampling struct {
+	Levels             []LogLevel    `json:"levels"`
+	Floor              float64       `json:"floor"`
+	Ceiling            float64       `json:"ceiling"`
+	ErrorRateThreshold float64       `json:"error_rate_threshold"`
+	Interval           time.Duration `json:"interval"`
+}
+
+// levelSampler keeps the effective per-level sample rates
+type levelSampler struct {
+	mutex sync.RWMutex
+	rates map[LogLevel]float64
+	stop  chan struct{}
+	once  sync.Once
+}
+
+// newLevelSampler creates a sampler from the configured rates
+func newLevelSampler(rates map[LogLevel]float64) *levelSampler {
+	s := &levelSampler{rates: make(map[LogLevel]float64, len(rates))}
+	for level, rate := range rates {
+		s.rates[level] = rate
+	}
+	return s
+}
+
+// keep decides randomly whether to keep a log at level
+func (s *levelSampler) keep(level LogLevel) bool {
+	s.mutex.RLock()
+	rate, ok := s.rates[level]
+	s.mutex.RUnlock()
+
+	if !ok || rate >= 1 {
+		return true
+	}
+	return rand.Float64() < rate
+}
+
+// set updates the rate of a level
+func (s *levelSampler) set(level LogLevel, rate float64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.rates[level] = rate
+}
+
+// snapshot returns a copy of the effective rates
+func (s *levelSampler) snapshot() map[LogLevel]float64 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	rates := make(map[LogLevel]float64, len(s.rates))
+	for level, rate := range s.rates {
+		rates[level] = rate
+	}
+	return rates
+}
+
+// startAdaptive adjusts the rates of the configured levels on every
+// interval until stopAdaptive is called
+func (s *levelSampler) startAdaptive(config AdaptiveSampling, stats *statsManager) {
+	levels := config.Levels
+	if len(levels) == 0 {
+		levels = []LogLevel{Debug}
+	}
+	interval := config.Interval
+	if interval <= 0 {
+		interval = DefaultAdaptiveSamplingInterval
+	}
+
+	for _, level := range levels {
+		s.set(level, config.Floor)
+	}
+
+	s.stop = make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		previous := stats.snapshot()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				current := stats.snapshot()
+				if current.TotalLogs < previous.TotalLogs || current.TotalErrors < previous.TotalErrors {
+					// The stats were reset during the interval
+					previous = Stats{}
+				}
+				rate := adaptiveRate(config, current.TotalLogs-previous.TotalLogs, current.TotalErrors-previous.TotalErrors)
+				for _, level := range levels {
+					s.set(level, rate)
+				}
+				previous = current
+			}
+		}
+	}()
+}
+
+// stopAdaptive stops adjusting the rates
+func (s *levelSampler) stopAdaptive() {
+	if s.stop == nil {
+		return
+	}
+	s.once.Do(func() { close(s.stop) })
+}
+
+// adaptiveRate computes the sample rate for the error rate of one interval
+func adaptiveRate(config AdaptiveSampling, logs, errors int64) float64 {
+	if logs <= 0 || config.ErrorRateThreshold <= 0 {
+		return config.Floor
+	}
+	ratio := float64(errors) / float64(logs) / config.ErrorRateThreshold
+	if ratio > 1 {
+		ratio = 1
+	}
+	return config.Floor + (config.Ceiling-config.Floor)*ratio
+}
+
+// EffectiveSampleRates returns the sample rate currently applied to each
+// level that is sampled, including rates adjusted by adaptive sampling.
+// Levels not listed are always kept.
+func (l *Logger) EffectiveSampleRates() map[LogLevel]float64 {
+	return l.sampler.snapshot()
+}
@@ -0,0 +1,108 @@
+package checklogs
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ErrClientClosed is returned for logs sent after Shutdown
+var ErrClientClosed = &CheckLogsError{Type: "ClientClosedError", Message: "logger is shut down"}
+
+// Shutdown stops the logger and its children from accepting new logs, which
+// are rejected with ErrClientClosed, stops background workers, then sends
+// the buffered logs and flushes the retry queue within ctx's deadline. Logs
+// that could not be delivered are reported in the returned error; those
+// left when ctx ends stay in the retry queue. On a child logger, Shutdown
+// only flushes the buffered logs like Flush, and the logger it was derived
+// from keeps running.
+func (l *Logger) Shutdown(ctx context.Context) error {
+	if l.root != nil {
+		return l.Flush(ctx)
+	}
+	if !l.closed.Swap(true) {
+		l.logSummary(ctx)
+	}
+	return l.shutdown(ctx)
+}
+
+// Close shuts the logger down like Shutdown, but only once: calls after the
+// logger is closed, by Close or Shutdown, do nothing and return nil, so it
+// is safe to defer alongside an explicit shutdown. Like Shutdown, closing a
+// child logger, such as one handed to a request handler, only flushes the
+// buffered logs.
+func (l *Logger) Close(ctx context.Context) error {
+	if l.root != nil {
+		return l.Flush(ctx)
+	}
+	if !l.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+	l.logSummary(ctx)
+	return l.shutdown(ctx)
+}
+
+// logSummary logs the stats of the logger's run when LogSummaryOnClose is
+// set. It is sent before the final flush, so it is delivered, or left in
+// the retry queue, by the time Close returns.
+func (l *Logger) logSummary(ctx context.Context) {
+	if !l.options.LogSummaryOnClose {
+		return
+	}
+	stats := l.stats.snapshot()
+	data := l.buildLogData(ctx, Info, "Logger closed", map[string]interface{}{
+		"total_logs":   stats.TotalLogs,
+		"total_errors": stats.TotalErrors,
+		"duration_ms":  time.Since(l.started).Milliseconds(),
+	})
+	l.sendLog(ctx, data, closing())
+}
+
+// owner returns the logger that owns the background workers: the root of a
+// child logger, or l itself
+func (l *Logger) owner() *Logger {
+	if l.root != nil {
+		return l.root
+	}
+	return l
+}
+
+// shutdown stops background work and makes the final flush
+func (l *Logger) shutdown(ctx context.Context) error {
+	if l.debouncer != nil {
+		l.debouncer.stop()
+	}
+	l.sampler.stopAdaptive()
+	if l.retryWorker != nil {
+		l.retryWorker.stop()
+	}
+	undelivered := 0
+	if l.buffer != nil {
+		undelivered += l.buffer.stop(ctx)
+	}
+	for _, data := range l.drainRetryQueue() {
+		if ctx.Err() != nil {
+			// Out of time: keep the rest queued, which a durable RetryQueue
+			// preserves for the next process
+			l.retryQueue.Add(data)
+			undelivered++
+			continue
+		}
+		if _, err := l.sendLog(ctx, data, replay()); err != nil {
+			undelivered++
+		}
+	}
+	for _, sink := range l.options.Sinks {
+		if flusher, ok := sink.(SinkFlusher); ok {
+			if err := flusher.Flush(ctx); err != nil && !l.options.Silent {
+				l.console.WriteString(fmt.Sprintf("[CHECKLOGS ERROR] sink: %s\n", err.Error()))
+			}
+		}
+	}
+	l.console.Flush()
+
+	if undelivered > 0 {
+		return &CheckLogsError{Type: "ShutdownError", Message: fmt.Sprintf("%d logs could not be delivered before shutdown", undelivered)}
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package checklogs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// waitForGoroutines waits for the number of goroutines to drop to at most
+// n, returning the last count seen
+func waitForGoroutines(n int) int {
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		count := runtime.NumGoroutine()
+		if count <= n || time.Now().After(deadline) {
+			return count
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestCloseStopsWorkers(t *testing.T) {
+	tests := []struct {
+		name  string
+		close func(ctx context.Context, logger *Logger) error
+	}{
+		{"Close", func(ctx context.Context, logger *Logger) error { return logger.Close(ctx) }},
+		{"Shutdown", func(ctx context.Context, logger *Logger) error { return logger.Shutdown(ctx) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			baseline := runtime.NumGoroutine()
+
+			logger := newTestLogger(t, server, func(o *Options) {
+				o.AutoRetry = true
+				o.RetryInterval = 10 * time.Millisecond
+				o.BufferSize = 10
+			})
+			child := logger.Child(map[string]interface{}{"component": "worker"})
+
+			if err := tt.close(context.Background(), logger); err != nil {
+				t.Fatalf("close: %v", err)
+			}
+			if count := waitForGoroutines(baseline); count > baseline {
+				t.Errorf("goroutines after close = %d, want at most %d", count, baseline)
+			}
+			if err := child.Info(context.Background(), "after close"); !errors.Is(err, ErrClientClosed) {
+				t.Errorf("child log after close = %v, want ErrClientClosed", err)
+			}
+		})
+	}
+}
+
+func TestCloseChildOnlyFlushes(t *testing.T) {
+	tests := []struct {
+		name  string
+		close func(ctx context.Context, child *Logger) error
+	}{
+		{"child Close", func(ctx context.Context, child *Logger) error { return child.Close(ctx) }},
+		{"child Shutdown", func(ctx context.Context, child *Logger) error { return child.Shutdown(ctx) }},
+		{"grandchild Close", func(ctx context.Context, child *Logger) error { return child.Child(nil).Close(ctx) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			server := newTestServer(t)
+			parent := newTestLogger(t, server, func(o *Options) {
+				o.BufferSize = 10
+				o.FlushInterval = time.Hour
+			})
+			defer parent.Close(ctx)
+			child := parent.Child(map[string]interface{}{"component": "handler"})
+
+			child.Info(ctx, "buffered")
+			if err := tt.close(ctx, child); err != nil {
+				t.Fatalf("close: %v", err)
+			}
+			if got := len(server.logs()); got != 1 {
+				t.Errorf("server received %d logs after the child closed, want the buffered 1", got)
+			}
+			for _, logger := range []*Logger{parent, child} {
+				if err := logger.Info(ctx, "still running"); err != nil {
+					t.Errorf("log after the child closed = %v, want nil", err)
+				}
+			}
+		})
+	}
+}
+
+func TestLogSummaryOnClose(t *testing.T) {
+	tests := []struct {
+		name      string
+		enabled   bool
+		buffered  bool
+		close     func(ctx context.Context, parent, child *Logger) error
+		wantCount int
+	}{
+		{"disabled", false, false, func(ctx context.Context, parent, child *Logger) error { return parent.Close(ctx) }, 0},
+		{"Close", true, false, func(ctx context.Context, parent, child *Logger) error { return parent.Close(ctx) }, 1},
+		{"Shutdown", true, false, func(ctx context.Context, parent, child *Logger) error { return parent.Shutdown(ctx) }, 1},
+		{"buffered", true, true, func(ctx context.Context, parent, child *Logger) error { return parent.Close(ctx) }, 1},
+		{"child Close", true, false, func(ctx context.Context, parent, child *Logger) error { return child.Close(ctx) }, 0},
+		{"closed twice", true, false, func(ctx context.Context, parent, child *Logger) error {
+			if err := parent.Close(ctx); err != nil {
+				return err
+			}
+			return parent.Shutdown(ctx)
+		}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			server := newTestServer(t)
+			parent := newTestLogger(t, server, func(o *Options) {
+				o.LogSummaryOnClose = tt.enabled
+				if tt.buffered {
+					o.BufferSize = 10
+				}
+			})
+			child := parent.Child(nil)
+
+			parent.Info(ctx, "work")
+			child.Warning(ctx, "more work")
+
+			if err := tt.close(ctx, parent, child); err != nil {
+				t.Fatalf("close: %v", err)
+			}
+
+			var summaries []LogData
+			for _, data := range server.logs() {
+				if data.Message == "Logger closed" {
+					summaries = append(summaries, data)
+				}
+			}
+			if len(summaries) != tt.wantCount {
+				t.Fatalf("server received %d summaries, want %d", len(summaries), tt.wantCount)
+			}
+			if tt.wantCount == 0 {
+				return
+			}
+			summary := summaries[0]
+			if summary.Level != Info {
+				t.Errorf("summary level = %s, want info", summary.Level)
+			}
+			if summary.Context["total_logs"] != float64(2) {
+				t.Errorf("total_logs = %v, want 2", summary.Context["total_logs"])
+			}
+			if summary.Context["total_errors"] != float64(0) {
+				t.Errorf("total_errors = %v, want 0", summary.Context["total_errors"])
+			}
+			if _, ok := summary.Context["duration_ms"]; !ok {
+				t.Errorf("summary has no duration_ms: %v", summary.Context)
+			}
+		})
+	}
+}
+
+func TestShutdownReportsUndeliveredBufferedLogs(t *testing.T) {
+	tests := []struct {
+		name            string
+		status          int
+		expired         bool
+		wantUndelivered int
+		wantQueued      int
+	}{
+		{"delivered", http.StatusOK, false, 0, 0},
+		{"permanent failure", http.StatusBadRequest, false, 3, 0},
+		{"context expired", http.StatusOK, true, 3, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			logger := newTestLogger(t, server, func(o *Options) {
+				o.BufferSize = 10
+				o.FlushInterval = time.Hour
+			})
+			for i := 0; i < 3; i++ {
+				logger.Info(context.Background(), "buffered")
+			}
+
+			server.setStatus(tt.status)
+			ctx, cancel := context.WithCancel(context.Background())
+			if tt.expired {
+				cancel()
+			}
+			defer cancel()
+
+			err := logger.Shutdown(ctx)
+			if tt.wantUndelivered == 0 {
+				if err != nil {
+					t.Errorf("Shutdown = %v, want nil", err)
+				}
+			} else {
+				var e *CheckLogsError
+				want := fmt.Sprintf("%d logs could not be delivered", tt.wantUndelivered)
+				if !errors.As(err, &e) || e.Type != "ShutdownError" || !strings.Contains(e.Message, want) {
+					t.Errorf("Shutdown = %v, want a ShutdownError for %d logs", err, tt.wantUndelivered)
+				}
+			}
+			if got := logger.GetRetryQueueSize(); got != tt.wantQueued {
+				t.Errorf("retry queue size = %d, want %d", got, tt.wantQueued)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package checklogs
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+// countingSink records the entries written to it and how often it was
+// flushed
+type countingSink struct {
+	mutex   sync.Mutex
+	written []LogData
+	flushes int
+}
+
+func (s *countingSink) Write(ctx context.Context, data LogData) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.written = append(s.written, data)
+	return nil
+}
+
+func (s *countingSink) Flush(ctx context.Context) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.flushes++
+	return nil
+}
+
+func TestSinkWrittenOnceAcrossReplays(t *testing.T) {
+	tests := []struct {
+		name    string
+		replays int
+	}{
+		{"no replay", 0},
+		{"one replay", 1},
+		{"several replays", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			server := newTestServer(t)
+			sink := &countingSink{}
+			logger := newTestLogger(t, server, func(o *Options) {
+				o.Sinks = []Sink{sink}
+			})
+
+			server.setStatus(http.StatusServiceUnavailable)
+			logger.Error(ctx, "outage")
+			for i := 0; i < tt.replays; i++ {
+				logger.FlushRetryQueue(ctx)
+			}
+			server.setStatus(http.StatusOK)
+			if got := logger.FlushRetryQueue(ctx); got != 1 {
+				t.Fatalf("flushed = %d, want 1", got)
+			}
+
+			if got := len(sink.written); got != 1 {
+				t.Errorf("sink received %d writes, want 1", got)
+			}
+		})
+	}
+}
+
+func TestShutdownFlushesSinks(t *testing.T) {
+	tests := []struct {
+		name  string
+		close func(ctx context.Context, l *Logger) error
+	}{
+		{"Close", func(ctx context.Context, l *Logger) error { return l.Close(ctx) }},
+		{"Shutdown", func(ctx context.Context, l *Logger) error { return l.Shutdown(ctx) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			sink := &countingSink{}
+			logger := newTestLogger(t, server, func(o *Options) {
+				o.Sinks = []Sink{sink}
+			})
+
+			if err := tt.close(context.Background(), logger); err != nil {
+				t.Fatalf("close: %v", err)
+			}
+			if sink.flushes != 1 {
+				t.Errorf("sink flushed %d times, want 1", sink.flushes)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package checklogs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// SlogHandlerOptions configures a handler created by NewSlogHandler
+type SlogHandlerOptions struct {
+	// Level is the minimum slog level handled, in addition to the logger's
+	// EnabledLevels. Nil handles every level.
+	Level slog.Leveler
+}
+
+// slogHandler is a slog.Handler that sends records through a Logger
+type slogHandler struct {
+	logger *Logger
+	opts   SlogHandlerOptions
+	attrs  map[string]interface{}
+	prefix string
+}
+
+// NewSlogHandler returns a slog.Handler that sends records to CheckLogs
+// through logger. Attributes become context fields, with the keys of
+// attributes inside groups qualified by the group names ("http.method").
+//
+//	slog.SetDefault(slog.New(checklogs.NewSlogHandler(logger, checklogs.SlogHandlerOptions{})))
+func NewSlogHandler(logger *Logger, opts SlogHandlerOptions) slog.Handler {
+	return &slogHandler{logger: logger, opts: opts}
+}
+
+// Enabled reports whether records at level are handled
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	if h.opts.Level != nil && level < h.opts.Level.Level() {
+		return false
+	}
+	return h.logger.IsEnabled(slogLevel(level))
+}
+
+// Handle sends one record
+func (h *slogHandler) Handle(ctx context.Context, record slog.Record) error {
+	fields := make(map[string]interface{}, len(h.attrs)+record.NumAttrs())
+	for k, v := range h.attrs {
+		fields[k] = v
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		addSlogAttr(fields, h.prefix, attr)
+		return true
+	})
+
+	data := h.logger.buildLogData(ctx, slogLevel(record.Level), record.Message, fields)
+	if !record.Time.IsZero() {
+		data.Timestamp = record.Time
+	}
+	if outcome, err := h.logger.sendLog(ctx, data); outcome == ValidationFailed {
+		return err
+	}
+	return nil
+}
+
+// WithAttrs returns a handler adding attrs to every record
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	child := h.clone()
+	for _, attr := range attrs {
+		addSlogAttr(child.attrs, child.prefix, attr)
+	}
+	return child
+}
+
+// WithGroup returns a handler qualifying the keys of later attributes with
+// name
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	child := h.clone()
+	child.prefix += name + "."
+	return child
+}
+
+// clone copies the handler and its accumulated attributes
+func (h *slogHandler) clone() *slogHandler {
+	attrs := make(map[string]interface{}, len(h.attrs))
+	for k, v := range h.attrs {
+		attrs[k] = v
+	}
+	return &slogHandler{logger: h.logger, opts: h.opts, attrs: attrs, prefix: h.prefix}
+}
+
+// addSlogAttr adds an attribute to fields, flattening groups into dotted
+// keys. Empty attributes and empty groups are ignored, and the attributes
+// of a group without a key are inlined, as slog specifies.
+func addSlogAttr(fields map[string]interface{}, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, member := range attr.Value.Group() {
+			addSlogAttr(fields, prefix, member)
+		}
+		return
+	}
+
+	fields[prefix+attr.Key] = slogValue(attr.Value)
+}
+
+// slogValue converts a resolved slog value to a context value, formatting
+// durations and times like the Duration and Time fields
+func slogValue(value slog.Value) interface{} {
+	switch value.Kind() {
+	case slog.KindString:
+		return value.String()
+	case slog.KindInt64:
+		return value.Int64()
+	case slog.KindUint64:
+		return value.Uint64()
+	case slog.KindFloat64:
+		return value.Float64()
+	case slog.KindBool:
+		return value.Bool()
+	case slog.KindDuration:
+		return value.Duration().Milliseconds()
+	case slog.KindTime:
+		return value.Time().Format(time.RFC3339Nano)
+	default:
+		if err, ok := value.Any().(error); ok {
+			return err.Error()
+		}
+		return value.Any()
+	}
+}
+
+// slogLevel maps a slog level to a LogLevel. Levels above Error, such as a
+// custom critical level, map to Critical.
+func slogLevel(level slog.Level) LogLevel {
+	switch {
+	case level < slog.LevelInfo:
+		return Debug
+	case level < slog.LevelWarn:
+		return Info
+	case level < slog.LevelError:
+		return Warning
+	case level == slog.LevelError:
+		return Error
+	default:
+		return Critical
+	}
+}
@@ -0,0 +1,113 @@
+package checklogs
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+func TestSlogHandlerFlattensGroups(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(logger *slog.Logger)
+		want map[string]interface{}
+	}{
+		{
+			name: "flat attributes",
+			log: func(logger *slog.Logger) {
+				logger.Info("request", "method", "GET", "status", 200)
+			},
+			want: map[string]interface{}{"method": "GET", "status": float64(200)},
+		},
+		{
+			name: "inline group",
+			log: func(logger *slog.Logger) {
+				logger.Info("request", slog.Group("http", "method", "GET", "status", 200))
+			},
+			want: map[string]interface{}{"http.method": "GET", "http.status": float64(200)},
+		},
+		{
+			name: "nested groups",
+			log: func(logger *slog.Logger) {
+				logger.Info("request", slog.Group("http", slog.Group("request", "method", "POST")))
+			},
+			want: map[string]interface{}{"http.request.method": "POST"},
+		},
+		{
+			name: "WithGroup qualifies later attributes",
+			log: func(logger *slog.Logger) {
+				logger.With("service", "billing").WithGroup("db").With("table", "users").Info("query", "rows", 3)
+			},
+			want: map[string]interface{}{"service": "billing", "db.table": "users", "db.rows": float64(3)},
+		},
+		{
+			name: "WithGroup nested with inline group",
+			log: func(logger *slog.Logger) {
+				logger.WithGroup("app").WithGroup("db").Info("query", slog.Group("pool", "size", 4))
+			},
+			want: map[string]interface{}{"app.db.pool.size": float64(4)},
+		},
+		{
+			name: "group without key is inlined",
+			log: func(logger *slog.Logger) {
+				logger.Info("request", slog.Group("", "method", "GET"))
+			},
+			want: map[string]interface{}{"method": "GET"},
+		},
+		{
+			name: "empty group is dropped",
+			log: func(logger *slog.Logger) {
+				logger.Info("request", slog.Group("http"), "method", "GET")
+			},
+			want: map[string]interface{}{"method": "GET"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			logger := newTestLogger(t, server, nil)
+
+			tt.log(slog.New(NewSlogHandler(logger, SlogHandlerOptions{})))
+
+			logs := server.logs()
+			if len(logs) != 1 {
+				t.Fatalf("server received %d logs, want 1", len(logs))
+			}
+			if !reflect.DeepEqual(logs[0].Context, tt.want) {
+				t.Errorf("context = %v, want %v", logs[0].Context, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlogHandlerLevels(t *testing.T) {
+	tests := []struct {
+		level slog.Level
+		want  LogLevel
+	}{
+		{slog.LevelDebug, Debug},
+		{slog.LevelInfo, Info},
+		{slog.LevelWarn, Warning},
+		{slog.LevelError, Error},
+		{slog.LevelError + 4, Critical},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			server := newTestServer(t)
+			logger := newTestLogger(t, server, nil)
+
+			slog.New(NewSlogHandler(logger, SlogHandlerOptions{})).Log(context.Background(), tt.level, "leveled")
+
+			logs := server.logs()
+			if len(logs) != 1 {
+				t.Fatalf("server received %d logs, want 1", len(logs))
+			}
+			if logs[0].Level != tt.want {
+				t.Errorf("level = %s, want %s", logs[0].Level, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package checklogs
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetricsContentType is the content type of the output of WriteMetrics, to
+// set on a /metrics response
+const MetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// Stats is a snapshot of a logger's local counters
+type Stats struct {
+	TotalLogs      int64              `json:"total_logs"`
+	TotalErrors    int64              `json:"total_errors"`
+	ErrorRate      float64            `json:"error_rate"`
+	LastLog        time.Time          `json:"last_log"`
+	RetryQueueSize int                `json:"retry_queue_size"`
+	BytesSent      int64              `json:"bytes_sent"`
+	ByLevel        map[LogLevel]int64 `json:"by_level"`
+}
+
+// statsManager counts the logs sent by a logger and the sends that failed
+type statsManager struct {
+	mutex       sync.RWMutex
+	totalLogs   int64
+	totalErrors int64
+	lastLog     time.Time
+	bytesSent   int64
+	byLevel     map[LogLevel]int64
+}
+
+// newStatsManager creates an empty stats manager
+func newStatsManager() *statsManager {
+	return &statsManager{byLevel: make(map[LogLevel]int64)}
+}
+
+// IncrementLogs counts a log that passed validation
+func (s *statsManager) IncrementLogs(level LogLevel) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.totalLogs++
+	s.byLevel[level]++
+	s.lastLog = time.Now()
+}
+
+// IncrementErrors counts a log that could not be delivered
+func (s *statsManager) IncrementErrors() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.totalErrors++
+}
+
+// AddBytes counts the size of a request body accepted by the API
+func (s *statsManager) AddBytes(n int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.bytesSent += int64(n)
+}
+
+// Reset zeroes the counters and returns their values just before
+func (s *statsManager) Reset() Stats {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stats := s.snapshotLocked()
+	s.totalLogs = 0
+	s.totalErrors = 0
+	s.lastLog = time.Time{}
+	s.bytesSent = 0
+	s.byLevel = make(map[LogLevel]int64)
+	return stats
+}
+
+// snapshot returns the current counters
+func (s *statsManager) snapshot() Stats {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.snapshotLocked()
+}
+
+// snapshotLocked copies the counters; the caller holds the mutex
+func (s *statsManager) snapshotLocked() Stats {
+	stats := Stats{
+		TotalLogs:   s.totalLogs,
+		TotalErrors: s.totalErrors,
+		LastLog:     s.lastLog,
+		BytesSent:   s.bytesSent,
+		ByLevel:     make(map[LogLevel]int64, len(s.byLevel)),
+	}
+	for level, count := range s.byLevel {
+		stats.ByLevel[level] = count
+	}
+	if s.totalLogs > 0 {
+		stats.ErrorRate = float64(s.totalErrors) / float64(s.totalLogs)
+	}
+	return stats
+}
+
+// GetStats returns the logger's local counters. Logs replayed from the
+// retry queue are counted once, when first logged. ByLevel is a copy the
+// caller may keep.
+func (l *Logger) GetStats() Stats {
+	stats := l.stats.snapshot()
+	stats.RetryQueueSize = l.GetRetryQueueSize()
+	return stats
+}
+
+// ResetStats zeroes the logger's local counters, for per-interval
+// reporting, and returns their values just before the reset. Reading and
+// resetting happen atomically, so no log is missed between two intervals:
+//
+//	interval := logger.ResetStats()
+//	fmt.Printf("error rate over the last minute: %.2f\n", interval.ErrorRate)
+func (l *Logger) ResetStats() Stats {
+	stats := l.stats.Reset()
+	stats.RetryQueueSize = l.GetRetryQueueSize()
+	return stats
+}
+
+// WriteMetrics writes the logger's stats and send latency percentiles in the
+// OpenMetrics text format, for serving from a /metrics endpoint without a
+// Prometheus client library
+func (l *Logger) WriteMetrics(w io.Writer) error {
+	stats := l.GetStats()
+	p50, p95, p99 := l.LatencyPercentiles()
+
+	levels := make([]string, 0, len(stats.ByLevel))
+	for level := range stats.ByLevel {
+		levels = append(levels, string(level))
+	}
+	sort.Strings(levels)
+	var byLevel strings.Builder
+	for _, level := range levels {
+		fmt.Fprintf(&byLevel, "checklogs_level_logs_total{level=%q} %d\n", level, stats.ByLevel[LogLevel(level)])
+	}
+
+	_, err := fmt.Fprintf(w, `# TYPE checklogs_logs counter
+# HELP checklogs_logs Logs that passed validation.
+checklogs_logs_total %d
+# TYPE checklogs_level_logs counter
+# HELP checklogs_level_logs Logs that passed validation, by level.
+%s# TYPE checklogs_errors counter
+# HELP checklogs_errors Logs that could not be delivered on the first attempt.
+checklogs_errors_total %d
+# TYPE checklogs_retry_queue_size gauge
+# HELP checklogs_retry_queue_size Logs waiting in the retry queue.
+checklogs_retry_queue_size %d
+# TYPE checklogs_sent_bytes counter
+# UNIT checklogs_sent_bytes bytes
+# HELP checklogs_sent_bytes Request bytes of the logs accepted by the API.
+checklogs_sent_bytes_total %d
+# TYPE checklogs_send_latency_seconds summary
+# UNIT checklogs_send_latency_seconds seconds
+# HELP checklogs_send_latency_seconds Latency of recent requests to the logs endpoint.
+checklogs_send_latency_seconds{quantile="0.5"} %g
+checklogs_send_latency_seconds{quantile="0.95"} %g
+checklogs_send_latency_seconds{quantile="0.99"} %g
+# EOF
+`, stats.TotalLogs, byLevel.String(), stats.TotalErrors, stats.RetryQueueSize, stats.BytesSent, p50.Seconds(), p95.Seconds(), p99.Seconds())
+	return err
+}
+
+// MergeStats combines the stats of several loggers. Totals are summed, the
+// error rate is recomputed from the combined totals rather than averaged,
+// and LastLog is the most recent of all.
+func MergeStats(stats ...Stats) Stats {
+	merged := Stats{ByLevel: make(map[LogLevel]int64)}
+	for _, s := range stats {
+		for level, count := range s.ByLevel {
+			merged.ByLevel[level] += count
+		}
+		merged.TotalLogs += s.TotalLogs
+		merged.TotalErrors += s.TotalErrors
+		merged.RetryQueueSize += s.RetryQueueSize
+		merged.BytesSent += s.BytesSent
+		if s.LastLog.After(merged.LastLog) {
+			merged.LastLog = s.LastLog
+		}
+	}
+	if merged.TotalLogs > 0 {
+		merged.ErrorRate = float64(merged.TotalErrors) / float64(merged.TotalLogs)
+	}
+	return merged
+}
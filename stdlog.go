@@ -0,0 +1,145 @@
+package checklogs
+
+import (
+	"context"
+	"io"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// stdFilePattern matches the "file.go:123: " header written by Lshortfile
+// and Llongfile
+var stdFilePattern = regexp.MustCompile(`^(.+?:\d+): `)
+
+// stdLogWriter receives the output of a standard library logger and sends
+// each entry to CheckLogs
+type stdLogWriter struct {
+	logger *Logger
+	level  LogLevel
+	std    *log.Logger
+}
+
+// WrapStdLogger returns a standard library logger whose output is sent to
+// CheckLogs at the given level. Prefix, date, time and file information
+// written according to the returned logger's prefix and flags (which may be
+// changed with SetPrefix and SetFlags) are parsed out of each entry into the
+// "prefix" and "file" context fields and the entry timestamp. Messages
+// longer than the message limit are truncated, as with Writer.
+func WrapStdLogger(logger *Logger, level LogLevel) *log.Logger {
+	w := &stdLogWriter{logger: logger, level: level}
+	w.std = log.New(w, "", 0)
+	return w.std
+}
+
+// Write sends one standard library log entry
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	line := strings.TrimSuffix(string(p), "\n")
+	message, fields, timestamp := parseStdLogLine(line, w.std.Prefix(), w.std.Flags())
+
+	ctx := context.Background()
+	data := w.logger.buildLogData(ctx, w.level, truncateString(message, w.logger.limits.get().MaxMessageLength), fields)
+	if !timestamp.IsZero() {
+		data.Timestamp = timestamp
+	}
+	if outcome, err := w.logger.sendLog(ctx, data); outcome == ValidationFailed {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// lineWriter sends each line written to it as a log message
+type lineWriter struct {
+	logger *Logger
+	level  LogLevel
+}
+
+// Writer returns an io.Writer that sends each line written to it as a log
+// at level, so existing output can be redirected without touching call
+// sites:
+//
+//	log.SetOutput(logger.Writer(checklogs.Info))
+//
+// Lines longer than the message limit are truncated. Unlike WrapStdLogger,
+// lines are sent verbatim, with any header the writer's producer adds.
+func (l *Logger) Writer(level LogLevel) io.Writer {
+	return &lineWriter{logger: l, level: level}
+}
+
+// Write sends every non-empty line of p
+func (w *lineWriter) Write(p []byte) (int, error) {
+	maxLength := w.logger.limits.get().MaxMessageLength
+	for _, line := range strings.Split(strings.TrimSuffix(string(p), "\n"), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if line == "" {
+			continue
+		}
+		ctx := context.Background()
+		data := w.logger.buildLogData(ctx, w.level, truncateString(line, maxLength))
+		if outcome, err := w.logger.sendLog(ctx, data); outcome == ValidationFailed {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// parseStdLogLine splits a standard library log line into its message,
+// header fields and timestamp
+func parseStdLogLine(line, prefix string, flags int) (string, map[string]interface{}, time.Time) {
+	fields := make(map[string]interface{})
+	var timestamp time.Time
+
+	if prefix != "" && flags&log.Lmsgprefix == 0 && strings.HasPrefix(line, prefix) {
+		line = line[len(prefix):]
+		fields["prefix"] = strings.TrimSpace(prefix)
+	}
+
+	location := time.Local
+	if flags&log.LUTC != 0 {
+		location = time.UTC
+	}
+
+	if flags&(log.Ldate|log.Ltime|log.Lmicroseconds) != 0 {
+		var layout string
+		if flags&log.Ldate != 0 {
+			layout = "2006/01/02 "
+		}
+		if flags&(log.Ltime|log.Lmicroseconds) != 0 {
+			layout += "15:04:05"
+			if flags&log.Lmicroseconds != 0 {
+				layout += ".000000"
+			}
+			layout += " "
+		}
+		if len(line) >= len(layout) {
+			if t, err := time.ParseInLocation(layout, line[:len(layout)], location); err == nil {
+				line = line[len(layout):]
+				timestamp = completeDate(t, flags, location)
+			}
+		}
+	}
+
+	if flags&(log.Lshortfile|log.Llongfile) != 0 {
+		if match := stdFilePattern.FindStringSubmatch(line); match != nil {
+			fields["file"] = match[1]
+			line = line[len(match[0]):]
+		}
+	}
+
+	if prefix != "" && flags&log.Lmsgprefix != 0 && strings.HasPrefix(line, prefix) {
+		line = line[len(prefix):]
+		fields["prefix"] = strings.TrimSpace(prefix)
+	}
+
+	return line, fields, timestamp
+}
+
+// completeDate fills in today's date for headers that only carry the time
+func completeDate(t time.Time, flags int, location *time.Location) time.Time {
+	if flags&log.Ldate != 0 {
+		return t
+	}
+	now := time.Now().In(location)
+	return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), location)
+}
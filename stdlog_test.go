@@ -0,0 +1,43 @@
+package checklogs
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestStdLoggersTruncateLongLines(t *testing.T) {
+	tests := []struct {
+		name   string
+		writer func(logger *Logger) io.Writer
+	}{
+		{"WrapStdLogger", func(logger *Logger) io.Writer { return WrapStdLogger(logger, Info).Writer() }},
+		{"Writer", func(logger *Logger) io.Writer { return logger.Writer(Info) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			logger := newTestLogger(t, server, func(o *Options) {
+				o.MaxMessageLength = 16
+			})
+
+			line := strings.Repeat("x", 100) + "\n"
+			n, err := io.WriteString(tt.writer(logger), line)
+			if err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			if n != len(line) {
+				t.Errorf("Write = %d, want %d", n, len(line))
+			}
+
+			logs := server.logs()
+			if len(logs) != 1 {
+				t.Fatalf("server received %d logs, want 1", len(logs))
+			}
+			if got := len(logs[0].Message); got > 16 || got == 0 {
+				t.Errorf("message length = %d, want 1..16", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package checklogs
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrSuppressed is returned in place of a send error identical to one
+// already returned within Options.ErrorThrottleWindow. The log itself is
+// handled as usual, for example queued for retry.
+var ErrSuppressed = &CheckLogsError{Type: "SuppressedError", Message: "identical error already reported recently"}
+
+// errorThrottle remembers when each failure signature was last returned
+type errorThrottle struct {
+	mutex  sync.Mutex
+	window time.Duration
+	last   map[string]time.Time
+}
+
+// newErrorThrottle creates an error throttle, or returns nil when window is
+// not positive
+func newErrorThrottle(window time.Duration) *errorThrottle {
+	if window <= 0 {
+		return nil
+	}
+	return &errorThrottle{window: window, last: make(map[string]time.Time)}
+}
+
+// filter returns err the first time its signature is seen in the window and
+// ErrSuppressed after that
+func (t *errorThrottle) filter(err error) error {
+	signature := err.Error()
+	if e, ok := err.(*CheckLogsError); ok {
+		signature = fmt.Sprintf("%s:%d:%s", e.Type, e.Code, e.Message)
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := time.Now()
+	if last, ok := t.last[signature]; ok && now.Sub(last) < t.window {
+		return ErrSuppressed
+	}
+
+	// Forget expired signatures so the map stays small
+	for k, last := range t.last {
+		if now.Sub(last) >= t.window {
+			delete(t.last, k)
+		}
+	}
+	t.last[signature] = now
+	return err
+}
@@ -0,0 +1,44 @@
+package checklogs
+
+import (
+	"testing"
+)
+
+func TestTimeFuncPanics(t *testing.T) {
+	tests := []struct {
+		name      string
+		timeFunc  func(logger *Logger) func()
+		wantLevel LogLevel
+		wantError bool
+	}{
+		{"TimeFunc does not recover", func(logger *Logger) func() { return logger.TimeFunc("job", "Running job") }, Info, false},
+		{"TimeFuncRecover logs the panic", func(logger *Logger) func() { return logger.TimeFuncRecover("job", "Running job") }, Critical, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			logger := newTestLogger(t, server, nil)
+
+			recovered := func() (r interface{}) {
+				defer func() { r = recover() }()
+				defer tt.timeFunc(logger)()
+				panic("boom")
+			}()
+			if recovered != "boom" {
+				t.Errorf("recovered %v, want the panic to resume", recovered)
+			}
+
+			logs := server.logs()
+			if len(logs) != 1 {
+				t.Fatalf("server received %d logs, want 1", len(logs))
+			}
+			if logs[0].Level != tt.wantLevel {
+				t.Errorf("level = %s, want %s", logs[0].Level, tt.wantLevel)
+			}
+			if _, ok := logs[0].Context["error"]; ok != tt.wantError {
+				t.Errorf("context = %v, want error field = %v", logs[0].Context, tt.wantError)
+			}
+		})
+	}
+}
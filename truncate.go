@@ -0,0 +1,71 @@
+package checklogs
+
+import "sort"
+
+// TruncatedKey is the context field set on logs shortened by
+// TruncateOversized
+const TruncatedKey = "_truncated"
+
+// truncateOversized shortens the fields of a log that exceed the size limits
+// so it can be sent instead of rejected. The message, source and detail are
+// cut, and the largest context values are dropped until the context fits.
+// A shortened log gets "_truncated": true in its context.
+func (l *Logger) truncateOversized(data *LogData) {
+	limits := l.limits.get()
+	truncated := false
+
+	if len(data.Message) > limits.MaxMessageLength {
+		data.Message = truncateString(data.Message, limits.MaxMessageLength)
+		truncated = true
+	}
+	if len(data.Source) > limits.MaxSourceLength {
+		data.Source = truncateString(data.Source, limits.MaxSourceLength)
+		truncated = true
+	}
+	if len(data.Detail) > limits.MaxDetailBytes {
+		data.Detail = truncateString(data.Detail, limits.MaxDetailBytes)
+		truncated = true
+	}
+
+	// Leave room for the flag itself
+	maxBytes := limits.MaxContextBytes - len(`,"`+TruncatedKey+`":true`)
+	maxKeys := limits.MaxContextKeys - 1
+	tooMany := func(n int) bool { return limits.MaxContextKeys > 0 && n > maxKeys }
+
+	if len(data.Context) > 0 && (truncated || tooMany(len(data.Context)) || getContextSize(data.Context) > maxBytes) {
+		sizes := make(map[string]int, len(data.Context))
+		keys := make([]string, 0, len(data.Context))
+		for k, v := range data.Context {
+			sizes[k] = getContextSize(v)
+			keys = append(keys, k)
+		}
+		// Largest first, by name among equals so the result is stable
+		sort.Slice(keys, func(i, j int) bool {
+			if sizes[keys[i]] != sizes[keys[j]] {
+				return sizes[keys[i]] > sizes[keys[j]]
+			}
+			return keys[i] < keys[j]
+		})
+
+		// Copy rather than modify a map the caller may still hold
+		pruned := make(map[string]interface{}, len(data.Context))
+		for k, v := range data.Context {
+			pruned[k] = v
+		}
+		for _, k := range keys {
+			if !tooMany(len(pruned)) && getContextSize(pruned) <= maxBytes {
+				break
+			}
+			delete(pruned, k)
+			truncated = true
+		}
+		data.Context = pruned
+	}
+
+	if truncated {
+		if data.Context == nil {
+			data.Context = make(map[string]interface{}, 1)
+		}
+		data.Context[TruncatedKey] = true
+	}
+}
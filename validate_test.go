@@ -0,0 +1,87 @@
+package checklogs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestContextSizeLimits(t *testing.T) {
+	big := strings.Repeat("x", DefaultMaxContextBytes)
+
+	tests := []struct {
+		name      string
+		configure func(*Options)
+		context   map[string]interface{}
+		wantErr   error
+		wantValue interface{}
+	}{
+		{"within the limit", nil, map[string]interface{}{"v": "small"}, nil, "small"},
+		{"over the default limit", nil, map[string]interface{}{"v": big}, ErrValidation, nil},
+		{"raised limit", func(o *Options) { o.MaxContextBytes = 2 * DefaultMaxContextBytes }, map[string]interface{}{"v": big}, nil, big},
+		{"value capped below the limit", func(o *Options) { o.MaxValueBytes = 10 }, map[string]interface{}{"v": big}, nil, "xxxxx..."},
+		{"non-string value replaced", func(o *Options) { o.MaxValueBytes = 10 }, map[string]interface{}{"v": []string{big}}, nil, "<oversized 5004 bytes>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			logger := newTestLogger(t, server, tt.configure)
+
+			err := logger.Info(context.Background(), "sized", tt.context)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("Info error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if got := len(server.logs()); got != 0 {
+					t.Errorf("server received %d logs, want 0", got)
+				}
+				return
+			}
+			logs := server.logs()
+			if len(logs) != 1 {
+				t.Fatalf("server received %d logs, want 1", len(logs))
+			}
+			if logs[0].Context["v"] != tt.wantValue {
+				t.Errorf("value = %.20v, want %.20v", logs[0].Context["v"], tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestLevelDecoding(t *testing.T) {
+	logLevel := func(v interface{}) LogLevel { return v.(*LogData).Level }
+	minLevel := func(v interface{}) LogLevel { return v.(*Options).MinLevel }
+
+	tests := []struct {
+		name      string
+		input     string
+		into      interface{}
+		level     func(interface{}) LogLevel
+		wantLevel LogLevel
+		wantErr   error
+	}{
+		{"known level in a log", `{"message":"m","level":"warning"}`, &LogData{}, logLevel, Warning, nil},
+		{"unknown level in a log", `{"message":"m","level":"trace"}`, &LogData{}, logLevel, "trace", nil},
+		{"known level in options", `{"min_level":"error"}`, &Options{}, minLevel, Error, nil},
+		{"unknown MinLevel", `{"min_level":"verbose"}`, &Options{}, nil, "", ErrValidation},
+		{"unknown EnabledLevels entry", `{"enabled_levels":["info","notice"]}`, &Options{}, nil, "", ErrValidation},
+		{"unknown SampleRates key", `{"sample_rates":{"trace":0.5}}`, &Options{}, nil, "", ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := json.Unmarshal([]byte(tt.input), tt.into)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("Unmarshal error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.level != nil {
+				if got := tt.level(tt.into); got != tt.wantLevel {
+					t.Errorf("level = %q, want %q", got, tt.wantLevel)
+				}
+			}
+		})
+	}
+}
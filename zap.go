@@ -0,0 +1,150 @@
+package checklogs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// ZapWriteSyncer ships entries encoded by zap's JSON encoder to CheckLogs.
+// It implements zapcore.WriteSyncer without importing zap, so plug it into
+// a core with any encoder config using the production or development keys:
+//
+//	core := zapcore.NewCore(
+//		zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+//		checklogs.NewZapWriteSyncer(logger),
+//		zap.InfoLevel,
+//	)
+type ZapWriteSyncer struct {
+	logger *Logger
+}
+
+// NewZapWriteSyncer creates a zap WriteSyncer that sends through logger
+func NewZapWriteSyncer(logger *Logger) *ZapWriteSyncer {
+	return &ZapWriteSyncer{logger: logger}
+}
+
+// Write parses one or more JSON-encoded zap entries and sends them. Entries
+// that fail to send are queued for retry as usual; only malformed or
+// invalid entries are reported back to zap.
+func (w *ZapWriteSyncer) Write(p []byte) (int, error) {
+	for _, line := range bytes.Split(p, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		ctx := context.Background()
+		data, err := w.logger.parseZapEntry(ctx, line)
+		if err != nil {
+			return 0, err
+		}
+		if outcome, err := w.logger.sendLog(ctx, data); outcome == ValidationFailed {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Sync flushes the retry queue
+func (w *ZapWriteSyncer) Sync() error {
+	w.logger.FlushRetryQueue(context.Background())
+	return nil
+}
+
+// Field names used by zap's production and development encoder configs
+var (
+	zapMessageKeys    = []string{"msg", "M"}
+	zapLevelKeys      = []string{"level", "L"}
+	zapTimeKeys       = []string{"ts", "T"}
+	zapLoggerKeys     = []string{"logger", "N"}
+	zapCallerKeys     = []string{"caller", "C"}
+	zapStacktraceKeys = []string{"stacktrace", "S"}
+)
+
+// parseZapEntry converts a JSON-encoded zap entry into a log entry, folding
+// zap's structured fields into the context
+func (l *Logger) parseZapEntry(ctx context.Context, line []byte) (LogData, error) {
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+
+	var fields map[string]interface{}
+	if err := decoder.Decode(&fields); err != nil {
+		return LogData{}, &CheckLogsError{Type: "ValidationError", Message: "invalid zap entry: " + err.Error()}
+	}
+
+	message, _ := takeField(fields, zapMessageKeys).(string)
+	levelName, _ := takeField(fields, zapLevelKeys).(string)
+	timestamp := parseZapTime(takeField(fields, zapTimeKeys))
+
+	if name, ok := takeField(fields, zapLoggerKeys).(string); ok {
+		fields["logger"] = name
+	}
+	if caller, ok := takeField(fields, zapCallerKeys).(string); ok {
+		fields["caller"] = caller
+	}
+	stacktrace, _ := takeField(fields, zapStacktraceKeys).(string)
+
+	data := l.buildLogData(ctx, zapLevel(levelName), message, fields)
+	data.Detail = stacktrace
+	if !timestamp.IsZero() {
+		data.Timestamp = timestamp
+	}
+	return data, nil
+}
+
+// takeField removes and returns the first present key among names
+func takeField(fields map[string]interface{}, names []string) interface{} {
+	for _, name := range names {
+		if v, ok := fields[name]; ok {
+			delete(fields, name)
+			return v
+		}
+	}
+	return nil
+}
+
+// zapLevel maps a zap level name to a LogLevel
+func zapLevel(name string) LogLevel {
+	switch strings.ToLower(name) {
+	case "debug":
+		return Debug
+	case "warn":
+		return Warning
+	case "error":
+		return Error
+	case "dpanic", "panic", "fatal":
+		return Critical
+	default:
+		return Info
+	}
+}
+
+// parseZapTime decodes the timestamp produced by zap's time encoders: epoch
+// seconds, milliseconds or nanoseconds, or a formatted string
+func parseZapTime(v interface{}) time.Time {
+	switch value := v.(type) {
+	case json.Number:
+		f, err := value.Float64()
+		if err != nil {
+			return time.Time{}
+		}
+		switch {
+		case f > 1e17:
+			return time.Unix(0, int64(f))
+		case f > 1e11:
+			return time.UnixMilli(int64(f))
+		default:
+			sec := int64(f)
+			return time.Unix(sec, int64((f-float64(sec))*1e9))
+		}
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.000Z0700"} {
+			if t, err := time.Parse(layout, value); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}